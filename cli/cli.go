@@ -0,0 +1,231 @@
+//go:build !tinygo && !gofluentlite
+
+// Package cli validates JSON, YAML, TOML and XML documents against registered Go structs, for checking config
+// files in CI. Register your types and hand the arguments to Run from a small main package:
+//
+//	func main() {
+//		cli.Register("AppConfig", config.App{})
+//		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+//	}
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	jsonserilizer "github.com/khaledibrahim1015/goFluentValidation.git/jsonSerilizer"
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+// Exit codes returned by Run
+const (
+	ExitValid   = 0
+	ExitInvalid = 1
+	ExitUsage   = 2
+	ExitOutput  = 3
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+	formatXML  = "xml"
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var (
+	typesMu sync.RWMutex
+	types   = make(map[string]reflect.Type)
+)
+
+// Register makes the struct type of prototype available to Run under name, ex: Register("AppConfig", App{})
+func Register(name string, prototype interface{}) {
+	structType := reflect.TypeOf(prototype)
+	if structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("cli: Register %s expects a struct, got %T", name, prototype))
+	}
+
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	types[name] = structType
+}
+
+// FileReport is the outcome of validating one document
+type FileReport struct {
+	File  string           `json:"file"`
+	Valid bool             `json:"valid"`
+	Error string           `json:"error,omitempty"`
+	Rules []FieldViolation `json:"errors,omitempty"`
+}
+
+// FieldViolation is a failed rule, as printed in machine readable reports
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Run validates the files named in args against a registered type and prints a report to stdout.
+// It returns ExitValid when every document is valid, ExitInvalid when one fails validation or cannot be
+// read, ExitUsage for bad arguments and ExitOutput when the report cannot be written. A file named -
+// is read from standard input.
+func Run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gofluentvalidate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	typeName := flags.String("type", "", "registered struct type the documents map onto")
+	format := flags.String("format", "", "document format, json, yaml, toml or xml (default from the file extension)")
+	output := flags.String("output", outputText, "report format, text or json")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: gofluentvalidate -type Name [-format json|yaml|toml|xml] [-output text|json] file...\n")
+		flags.PrintDefaults()
+		fmt.Fprintf(stderr, "registered types: %s\n", strings.Join(registeredTypes(), ", "))
+	}
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+
+	typesMu.RLock()
+	structType, ok := types[*typeName]
+	typesMu.RUnlock()
+	if !ok || flags.NArg() == 0 || *output != outputText && *output != outputJSON {
+		flags.Usage()
+		return ExitUsage
+	}
+
+	code := ExitValid
+	var reports []FileReport
+	for _, file := range flags.Args() {
+		report := validateFile(file, *format, structType)
+		if !report.Valid {
+			code = ExitInvalid
+		}
+		reports = append(reports, report)
+	}
+
+	if *output == outputJSON {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(reports); err != nil {
+			fmt.Fprintln(stderr, err)
+			return ExitOutput
+		}
+		return code
+	}
+	for _, report := range reports {
+		printText(stdout, report)
+	}
+	return code
+}
+
+// validateFile decodes one document onto a new value of structType and validates it
+func validateFile(file, format string, structType reflect.Type) FileReport {
+	report := FileReport{File: file}
+
+	data, err := readFile(file)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	if format == "" {
+		format = formatFromExtension(file)
+	}
+
+	var doc jsonserilizer.Result
+	switch format {
+	case formatJSON:
+		doc, err = jsonserilizer.JSON.Decode(data)
+	case formatYAML:
+		doc, err = jsonserilizer.YAML.Decode(data)
+	case formatTOML:
+		doc, err = jsonserilizer.TOML.Decode(data)
+	case formatXML:
+		doc, err = jsonserilizer.XML.Decode(data)
+	default:
+		err = fmt.Errorf("unknown format %q, use json, yaml, toml or xml", format)
+	}
+	if err != nil {
+		report.Error = fmt.Sprintf("cannot decode document: %v", err)
+		return report
+	}
+
+	target := reflect.New(structType)
+	if err := jsonserilizer.Deserialize(doc, target.Interface()); err != nil {
+		report.Error = fmt.Sprintf("cannot map document onto %s: %v", structType.Name(), err)
+		return report
+	}
+
+	err = validator.Validate(target.Interface())
+	if errs, ok := err.(validator.ValidationErrors); ok {
+		for _, errVal := range errs {
+			report.Rules = append(report.Rules, FieldViolation{Field: errVal.Field, Rule: errVal.Rule, Code: errVal.Code, Message: errVal.Message})
+		}
+		return report
+	}
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Valid = true
+	return report
+}
+
+// readFile reads a file, or standard input for -
+func readFile(file string) ([]byte, error) {
+	if file == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(file)
+}
+
+// formatFromExtension guesses the document format, JSON unless the file ends in .yaml, .yml, .toml or .xml
+func formatFromExtension(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return formatYAML
+	case ".toml":
+		return formatTOML
+	case ".xml":
+		return formatXML
+	}
+	return formatJSON
+}
+
+// printText writes the human readable report of one document
+func printText(w io.Writer, report FileReport) {
+	switch {
+	case report.Valid:
+		fmt.Fprintf(w, "%s: ok\n", report.File)
+	case report.Error != "":
+		fmt.Fprintf(w, "%s: error: %s\n", report.File, report.Error)
+	default:
+		fmt.Fprintf(w, "%s: %d problem(s)\n", report.File, len(report.Rules))
+		for _, violation := range report.Rules {
+			fmt.Fprintf(w, "  %s: %s (%s)\n", violation.Field, violation.Message, violation.Code)
+		}
+	}
+}
+
+// registeredTypes lists the registered type names, sorted
+func registeredTypes() []string {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
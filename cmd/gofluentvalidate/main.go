@@ -0,0 +1,19 @@
+//go:build !tinygo && !gofluentlite
+
+// Command gofluentvalidate validates JSON, YAML, TOML and XML documents against Go structs.
+// This build knows the example types of the validator package, projects register their own
+// types with the cli package from a copy of this main.
+package main
+
+import (
+	"os"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/cli"
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+func main() {
+	cli.Register("User", validator.User{})
+	cli.Register("Person", validator.Person{})
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -1,3 +1,12 @@
 module github.com/khaledibrahim1015/goFluentValidation.git
 
 go 1.22.1
+
+require (
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+)
+
+require golang.org/x/text v0.21.0
+
+require gopkg.in/yaml.v3 v3.0.1
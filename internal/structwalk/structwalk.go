@@ -0,0 +1,103 @@
+// Package structwalk is the reflection walk shared by the validator and the serializer: the
+// fields of a struct type, read once per type, and the pointer and struct checks both make on the
+// values they walk, so both packages agree on which fields exist and which structs are promoted.
+package structwalk
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// jsonTag is the struct tag of encoding/json
+const jsonTag = "json"
+
+// Field is a field of a struct type
+type Field struct {
+	reflect.StructField
+	// Owner is the struct type holding the field and Pos its position there
+	Owner reflect.Type
+	Pos   int
+	// JSONName is the name given by the json tag, empty without one. JSONSkip is set for
+	// `json:"-"`, `json:"-,"` names the field - like encoding/json.
+	JSONName string
+	JSONSkip bool
+	// JSONOptions are the options of the json tag ex: [omitempty]
+	JSONOptions []string
+	// Promoted is set for embedded structs, or pointers to structs, whose fields Go promotes to the
+	// holding struct. They are walked even when unexported, their own fields may be exported.
+	Promoted bool
+}
+
+// Walkable reports whether the field can be read by reflect: exported, or a promoted struct
+func (f Field) Walkable() bool {
+	return f.IsExported() || f.Promoted
+}
+
+// HasJSONOption reports whether the json tag of the field holds opt ex: omitempty
+func (f Field) HasJSONOption(opt string) bool {
+	for _, option := range f.JSONOptions {
+		if option == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldCache holds the []Field of every struct type walked
+var fieldCache sync.Map
+
+// Fields returns the fields of the struct type typ in order, read once per type
+func Fields(typ reflect.Type) []Field {
+	if fields, ok := fieldCache.Load(typ); ok {
+		return fields.([]Field)
+	}
+	fields := make([]Field, typ.NumField())
+	for i := range fields {
+		field := typ.Field(i)
+		fields[i] = Field{StructField: field, Owner: typ, Pos: i, Promoted: field.Anonymous && IsStruct(field.Type)}
+		if tag, ok := field.Tag.Lookup(jsonTag); ok {
+			fields[i].JSONName, fields[i].JSONOptions, fields[i].JSONSkip = ParseTag(tag)
+		}
+	}
+	cached, _ := fieldCache.LoadOrStore(typ, fields)
+	return cached.([]Field)
+}
+
+// ParseTag reads a tag with the syntax of the json tag ex: `name,omitempty`, skip is set for "-"
+// while "-," names the field -
+func ParseTag(tag string) (name string, opts []string, skip bool) {
+	if tag == "-" {
+		return "", nil, true
+	}
+	name, rest, _ := strings.Cut(tag, ",")
+	if rest != "" {
+		opts = strings.Split(rest, ",")
+	}
+	return name, opts, false
+}
+
+// StructType returns typ, or the type it points to
+func StructType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Pointer {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// IsStruct reports whether typ is a struct or a pointer to a struct
+func IsStruct(typ reflect.Type) bool {
+	return StructType(typ).Kind() == reflect.Struct
+}
+
+// Indirect returns the struct val holds directly or through a non nil pointer, ok is false for
+// other values
+func Indirect(val reflect.Value) (reflect.Value, bool) {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return reflect.Value{}, false
+		}
+		val = val.Elem()
+	}
+	return val, val.Kind() == reflect.Struct
+}
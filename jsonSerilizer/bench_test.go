@@ -0,0 +1,70 @@
+package jsonserilizer
+
+import "testing"
+
+// benchFlat only holds scalars, Serialize takes the path of scalarFields
+type benchFlat struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Email  string  `json:"email"`
+	Age    int     `json:"age"`
+	Score  float64 `json:"score"`
+	Active bool    `json:"active"`
+	Role   string  `json:"role"`
+	Note   string  `json:"note,omitempty"`
+}
+
+// benchGeneral holds the fields of benchFlat and a nil pointer, which sends it down the general path
+type benchGeneral struct {
+	ID     int64   `json:"id"`
+	Name   string  `json:"name"`
+	Email  string  `json:"email"`
+	Age    int     `json:"age"`
+	Score  float64 `json:"score"`
+	Active bool    `json:"active"`
+	Role   string  `json:"role"`
+	Note   *string `json:"note,omitempty"`
+}
+
+var (
+	benchFlatUser    = benchFlat{ID: 7, Name: "Ada", Email: "ada@example.com", Age: 36, Score: 9.5, Active: true, Role: "admin"}
+	benchGeneralUser = benchGeneral{ID: 7, Name: "Ada", Email: "ada@example.com", Age: 36, Score: 9.5, Active: true, Role: "admin"}
+)
+
+func BenchmarkSerialize(b *testing.B) {
+	b.Run("flat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Serialize(benchFlatUser)
+		}
+	})
+	b.Run("general", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Serialize(benchGeneralUser)
+		}
+	})
+}
+
+// BenchmarkDeserialize runs the shapes of BenchmarkSerialize, Deserialize takes the same path for both
+func BenchmarkDeserialize(b *testing.B) {
+	r := Serialize(benchFlatUser)
+	b.Run("flat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var user benchFlat
+			if err := Deserialize(r, &user); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("general", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var user benchGeneral
+			if err := Deserialize(r, &user); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
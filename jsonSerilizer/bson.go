@@ -0,0 +1,54 @@
+package jsonserilizer
+
+import (
+	"reflect"
+	"strings"
+)
+
+// bsonTag is the struct tag read WithBSON ex: `bson:"_id,omitempty"`
+const bsonTag = "bson"
+
+// bsonIDKey is the key of the primary key of a MongoDB document
+const bsonIDKey = "_id"
+
+// WithBSON shapes the Result like the MongoDB drivers do, so it can be inserted as a document
+// directly. The bson tags are read: `bson:"name"` names the key, `bson:",inline"` merges a struct
+// into its parent like squash or collects the leftover keys into a map like remain, and omitempty
+// and "-" work like their json counterparts. A field without a bson tag keyed id, regardless of
+// case, is keyed _id. time.Time values are kept as they are rather than formatted, the drivers
+// write them as BSON dates, and []byte as BSON binary. Use it with Deserialize too, to read the
+// _id key back.
+func WithBSON() Option {
+	return func(o *options) {
+		o.bson = true
+	}
+}
+
+// bsonFieldInfo applies the bson tag of field to info, the json tag still applies to fields
+// without one
+func bsonFieldInfo(field reflect.StructField, info fieldInfo) fieldInfo {
+	tag, ok := field.Tag.Lookup(bsonTag)
+	if !ok {
+		if !info.skip && strings.EqualFold(info.key, "id") {
+			info.key = bsonIDKey
+		}
+		return info
+	}
+	if tag == "-" {
+		return fieldInfo{skip: true}
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name != "" {
+		info.key, info.tagged = name, true
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "omitempty":
+			info.omitEmpty = true
+		case "inline":
+			info.squash = isSquashable(field.Type)
+			info.remain = isRemainable(field.Type)
+		}
+	}
+	return info
+}
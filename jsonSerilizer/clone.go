@@ -0,0 +1,83 @@
+package jsonserilizer
+
+import "reflect"
+
+// Clone returns a deep copy of v ex: to snapshot a request before mutating it. Pointers, slices, maps
+// and interfaces are copied with the structs they hold, all fields included whatever their tags, and
+// shared or cyclic pointers stay shared in the copy. Unexported fields, which reflect cannot set,
+// are copied as they are, and channels and functions are shared.
+func Clone[T any](v T) T {
+	var out T
+	c := cloner{copies: make(map[pointerKey]reflect.Value)}
+	c.copy(reflect.ValueOf(&out).Elem(), reflect.ValueOf(&v).Elem())
+	return out
+}
+
+// cloner holds the state of a Clone call
+type cloner struct {
+	// copies holds the copy of every pointer already copied
+	copies map[pointerKey]reflect.Value
+}
+
+// copy stores a deep copy of src into dst, a settable value of the same type
+func (c *cloner) copy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		key := pointerKey{addr: src.Pointer(), typ: src.Type()}
+		if copied, ok := c.copies[key]; ok {
+			dst.Set(copied)
+			return
+		}
+		elem := reflect.New(src.Type().Elem())
+		c.copies[key] = elem
+		c.copy(elem.Elem(), src.Elem())
+		dst.Set(elem)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		c.copy(elem, src.Elem())
+		dst.Set(elem)
+	case reflect.Struct:
+		// copies the unexported fields, the exported ones are then copied deeply
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				c.copy(dst.Field(i), src.Field(i))
+			}
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		elems := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		for i := 0; i < src.Len(); i++ {
+			c.copy(elems.Index(i), src.Index(i))
+		}
+		dst.Set(elems)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(dst.Index(i), src.Index(i))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			key := reflect.New(src.Type().Key()).Elem()
+			c.copy(key, iter.Key())
+			val := reflect.New(src.Type().Elem()).Elem()
+			c.copy(val, iter.Value())
+			m.SetMapIndex(key, val)
+		}
+		dst.Set(m)
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,148 @@
+package jsonserilizer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Codec writes and reads a Result in a document format, so the same structs and tags can be used
+// with JSON, YAML and other formats through MarshalWith and UnmarshalWith
+type Codec interface {
+	// Encode writes r as a document
+	Encode(r Result) ([]byte, error)
+	// Decode reads a document holding a single object
+	Decode(data []byte) (Result, error)
+}
+
+// JSON is the codec of Marshal and Unmarshal
+var JSON Codec = jsonCodec{}
+
+// optionsCodec is implemented by codecs relying on options of their own ex: XML reads the xml tags
+type optionsCodec interface {
+	options() []Option
+}
+
+// codecOptions returns opts preceded by the options of codec
+func codecOptions(codec Codec, opts []Option) []Option {
+	if c, ok := codec.(optionsCodec); ok {
+		return append(c.options(), opts...)
+	}
+	return opts
+}
+
+// MarshalWith writes a struct, or a pointer to a struct, with codec ex: MarshalWith(YAML, cfg)
+func MarshalWith(codec Codec, v interface{}, opts ...Option) ([]byte, error) {
+	o := newOptions(codecOptions(codec, opts))
+	r, err := o.structResult("Marshal", v)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := codec.(jsonCodec); ok {
+		return o.encodeJSON(r)
+	}
+	return codec.Encode(r)
+}
+
+// UnmarshalWith reads a document with codec and populates the struct out points to like Deserialize
+func UnmarshalWith(codec Codec, data []byte, out interface{}, opts ...Option) error {
+	r, err := decode(codec, data, opts)
+	if err != nil {
+		return err
+	}
+	return Deserialize(r, out, codecOptions(codec, opts)...)
+}
+
+// formatValue adapts val, as decoded by a codec with conventions of its own, to field
+func (d *decoder) formatValue(field reflect.Value, val interface{}) interface{} {
+	switch {
+	case d.xml:
+		val = d.xmlValue(field, val)
+	case d.csv:
+		val = d.csvValue(field, val)
+	case d.values:
+		val = valuesValue(field, val)
+	case d.flat:
+		val = flatValue(field, val)
+	}
+	if d.weak {
+		val = weakValue(field, val)
+	}
+	return val
+}
+
+// singleElement turns a lone value for a slice or array field into a list of one, for formats
+// repeating a key or an element per list entry such as XML and query strings
+func singleElement(field reflect.Value, val interface{}) interface{} {
+	if _, ok := val.([]interface{}); ok || val == nil {
+		return val
+	}
+	isBytes := field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8
+	if field.Kind() == reflect.Slice && !isBytes || field.Kind() == reflect.Array {
+		return []interface{}{val}
+	}
+	return val
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(r Result) ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (jsonCodec) Decode(data []byte) (Result, error) {
+	var r Result
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// plainValue prepares a Result for encoders without the conventions of encoding/json:
+// []byte becomes its base64 text, which Deserialize reads back
+func plainValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case Result:
+		return plainValue(map[string]interface{}(v))
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[key] = plainValue(elem)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = plainValue(elem)
+		}
+		return out
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	}
+	return val
+}
+
+// stringKeys turns the map[interface{}]interface{} YAML decodes mappings with non string keys into
+// into map[string]interface{}, recursively
+func stringKeys(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[fmt.Sprint(key)] = stringKeys(elem)
+		}
+		return out
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = stringKeys(elem)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = stringKeys(elem)
+		}
+		return v
+	}
+	return val
+}
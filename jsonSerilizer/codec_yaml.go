@@ -0,0 +1,33 @@
+//go:build !tinygo && !gofluentlite
+
+package jsonserilizer
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML reads and writes YAML documents, such as configuration files. The lite profile leaves it out.
+var YAML Codec = yamlCodec{}
+
+type yamlCodec struct{}
+
+func (yamlCodec) Encode(r Result) ([]byte, error) {
+	return yaml.Marshal(plainValue(r))
+}
+
+func (yamlCodec) Decode(data []byte) (Result, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return Result{}, nil
+	}
+	r, ok := stringKeys(doc).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("yaml: expected a mapping, got %s", typeName(doc))
+	}
+	return r, nil
+}
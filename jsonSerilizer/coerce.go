@@ -0,0 +1,110 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// WithStringCoercion lets Deserialize read numbers and booleans written as strings ex: "42" into an int
+// field and "true" into a bool field, and write numbers and booleans into string fields ex: 42 as "42"
+func WithStringCoercion() Option {
+	return func(o *options) {
+		o.stringCoercion = true
+	}
+}
+
+// WithWeaklyTypedInput lets Deserialize store strings, numbers and booleans into fields of any of
+// these types, for tolerant ingestion of payloads from loosely typed producers. It extends
+// WithStringCoercion: booleans become 1 or 0 in number fields, numbers are true when not 0 in bool
+// fields, empty strings zero number and bool fields, and a single value fills a slice of one.
+// Numbers that do not fit their field are still reported.
+func WithWeaklyTypedInput() Option {
+	return func(o *options) {
+		o.weak = true
+		o.stringCoercion = true
+	}
+}
+
+// weakValue adapts val to field for WithWeaklyTypedInput, strings are left to coerceString
+func weakValue(field reflect.Value, val interface{}) interface{} {
+	if num, ok := val.(json.Number); ok && field.Kind() == reflect.Bool {
+		f, err := num.Float64()
+		if err != nil {
+			return val
+		}
+		val = f
+	}
+	switch rVal := reflect.ValueOf(val); {
+	case !rVal.IsValid():
+		return val
+	case field.Kind() == reflect.Bool && isNumber(rVal.Kind()):
+		return !rVal.IsZero()
+	case isNumber(field.Kind()) && rVal.Kind() == reflect.Bool:
+		if rVal.Bool() {
+			return float64(1)
+		}
+		return float64(0)
+	case rVal.Kind() == reflect.String && rVal.Len() == 0 && (field.Kind() == reflect.Bool || isNumber(field.Kind())):
+		return reflect.Zero(field.Type()).Interface()
+	}
+	return singleElement(field, val)
+}
+
+// coerceString converts between strings and numbers or booleans, ok is false when neither side is a string
+func coerceString(field reflect.Value, rVal reflect.Value) (ok bool, err error) {
+	if rVal.Kind() == reflect.String {
+		return parseString(field, rVal.String())
+	}
+	if field.Kind() != reflect.String {
+		return false, nil
+	}
+
+	switch {
+	case rVal.Kind() == reflect.Bool:
+		field.SetString(strconv.FormatBool(rVal.Bool()))
+	case rVal.CanInt():
+		field.SetString(strconv.FormatInt(rVal.Int(), 10))
+	case rVal.CanUint():
+		field.SetString(strconv.FormatUint(rVal.Uint(), 10))
+	case rVal.CanFloat():
+		field.SetString(strconv.FormatFloat(rVal.Float(), 'f', -1, rVal.Type().Bits()))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseString parses text into a number or boolean field
+func parseString(field reflect.Value, text string) (ok bool, err error) {
+	switch {
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return true, fmt.Errorf("cannot parse %q", text)
+		}
+		field.SetBool(b)
+	case field.CanInt():
+		n, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return true, fmt.Errorf("cannot parse %q", text)
+		}
+		field.SetInt(n)
+	case field.CanUint():
+		n, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return true, fmt.Errorf("cannot parse %q", text)
+		}
+		field.SetUint(n)
+	case field.CanFloat():
+		f, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return true, fmt.Errorf("cannot parse %q", text)
+		}
+		field.SetFloat(f)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
@@ -0,0 +1,177 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// serializeSlice converts the elements of a slice or an array into a []interface{}
+func (o options) serializeSlice(val reflect.Value) []interface{} {
+	out := make([]interface{}, val.Len())
+	for i := range out {
+		o.cycles.push(pathElem{index: i})
+		out[i] = o.serializeValue(val.Index(i))
+		o.cycles.pop()
+	}
+	return out
+}
+
+// serializeMap converts a map with string or integer keys into a map[string]interface{},
+// ok is false for other key types
+func (o options) serializeMap(val reflect.Value) (map[string]interface{}, bool) {
+	out := make(map[string]interface{}, val.Len())
+	iter := val.MapRange()
+	for iter.Next() {
+		key, ok := mapKey(iter.Key())
+		if !ok {
+			return nil, false
+		}
+		o.cycles.push(pathElem{key: key, mapKey: true})
+		out[key] = o.serializeValue(iter.Value())
+		o.cycles.pop()
+	}
+	return out, true
+}
+
+// mapKey formats a map key the way JSON objects hold it
+func mapKey(key reflect.Value) (string, bool) {
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(key.Uint(), 10), true
+	}
+	return "", false
+}
+
+// parseMapKey converts a JSON object key into a key of keyType
+func parseMapKey(key string, keyType reflect.Type) (reflect.Value, error) {
+	out := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		out.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(key, 10, keyType.Bits())
+		if err != nil {
+			return out, fmt.Errorf("invalid map key %q", key)
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(key, 10, keyType.Bits())
+		if err != nil {
+			return out, fmt.Errorf("invalid map key %q", key)
+		}
+		out.SetUint(n)
+	default:
+		return out, fmt.Errorf("unsupported map key type")
+	}
+	return out, nil
+}
+
+// setSlice fills a slice or an array field element by element. Like encoding/json, extra elements
+// are dropped and missing ones zeroed for arrays.
+func (d *decoder) setSlice(field reflect.Value, elems []interface{}, path string) {
+	target := field
+	if field.Kind() == reflect.Slice {
+		target = reflect.MakeSlice(field.Type(), len(elems), len(elems))
+	}
+	for i := 0; i < target.Len(); i++ {
+		if i >= len(elems) {
+			target.Index(i).SetZero()
+			continue
+		}
+		d.setField(target.Index(i), elems[i], fmt.Sprintf("%s[%d]", path, i))
+	}
+	field.Set(target)
+}
+
+// setMap fills a map field entry by entry, converting the keys and the values
+func (d *decoder) setMap(field reflect.Value, entries map[string]interface{}, path string) {
+	target := reflect.MakeMapWithSize(field.Type(), len(entries))
+	if d.patch {
+		// entries are merged into a copy of the current map, null removes an entry
+		iter := field.MapRange()
+		for iter.Next() {
+			target.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	// sorted keys keep the order of the errors stable
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		val := entries[key]
+		entryPath := fmt.Sprintf("%s[%s]", path, key)
+		keyVal, err := parseMapKey(key, field.Type().Key())
+		if err != nil {
+			d.errs = append(d.errs, &FieldTypeError{Field: entryPath, Expected: field.Type().Key().String(), Got: "string", Reason: err.Error()})
+			continue
+		}
+		if d.patch && val == nil {
+			target.SetMapIndex(keyVal, reflect.Value{})
+			continue
+		}
+		elem := reflect.New(field.Type().Elem()).Elem()
+		if current := target.MapIndex(keyVal); current.IsValid() {
+			elem.Set(current)
+		}
+		d.setField(elem, val, entryPath)
+		target.SetMapIndex(keyVal, elem)
+	}
+	field.Set(target)
+}
+
+// setPath stores val in r under the keys of path ex: [Home City], creating the maps of nested structs
+func setPath(r map[string]interface{}, path []string, val interface{}) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := r[key].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			r[key] = nested
+		}
+		r = nested
+	}
+	r[path[len(path)-1]] = val
+}
+
+// textValue returns the text of a scalar, floats without exponent ex: 1000000 and durations as
+// time.ParseDuration reads them ex: 1m30s
+func textValue(val interface{}) string {
+	switch v := val.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(val)
+}
+
+// isNestedStruct reports whether a field of type typ is flattened into the keys of its parent by
+// formats without nesting such as CSV: structs, or pointers to structs, without a representation
+// of their own such as time.Time or a ResultMarshaler. Types found in parents are not, so a type
+// holding itself ex: a linked list node ends the flattening.
+func isNestedStruct(typ reflect.Type, parents []reflect.Type) bool {
+	typ = structType(typ)
+	if typ.Kind() != reflect.Struct || typ == timeType {
+		return false
+	}
+	for _, parent := range parents {
+		if parent == typ {
+			return false
+		}
+	}
+	for _, iface := range []reflect.Type{resultMarshalerType, marshalerType} {
+		if typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ConverterFunc converts a value decoded into a Result to the type of a field
+type ConverterFunc func(val interface{}) (interface{}, error)
+
+// converter is a registered conversion from a value type
+type converter struct {
+	from reflect.Type
+	fn   ConverterFunc
+}
+
+var (
+	convertersMu sync.RWMutex
+	// converters holds the conversions by target type, in registration order
+	converters = make(map[reflect.Type][]converter)
+)
+
+// RegisterConverter makes Deserialize store values of type from into fields of type to with fn ex:
+// RegisterConverter(reflect.TypeOf(""), reflect.TypeOf(uuid.UUID{}), parseUUID). A numeric from type
+// also converts the other numbers that fit it, so an int64 converter reads the float64 of JSON
+// numbers ex: int64 epochs into time.Time fields. Registered converters win over the built-in
+// conversions, a later registration replaces an earlier one for the same types.
+func RegisterConverter(from, to reflect.Type, fn ConverterFunc) {
+	if from == nil || to == nil || fn == nil {
+		panic("jsonserilizer: RegisterConverter expects two types and a function")
+	}
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	for i, c := range converters[to] {
+		if c.from == from {
+			converters[to][i].fn = fn
+			return
+		}
+	}
+	converters[to] = append(converters[to], converter{from: from, fn: fn})
+}
+
+// convert stores val into field with a registered converter, handled is false when none applies
+func (d *decoder) convert(field reflect.Value, val interface{}, path string) (handled bool) {
+	convertersMu.RLock()
+	registered := converters[field.Type()]
+	convertersMu.RUnlock()
+	if len(registered) == 0 {
+		return false
+	}
+
+	rVal := reflect.ValueOf(val)
+	for _, c := range registered {
+		arg, ok := convertibleTo(rVal, c.from)
+		if !ok {
+			continue
+		}
+		out, err := c.fn(arg.Interface())
+		if err != nil {
+			d.fail(path, field, val, err.Error())
+			return true
+		}
+		outVal := reflect.ValueOf(out)
+		if !outVal.IsValid() || !outVal.Type().AssignableTo(field.Type()) {
+			d.fail(path, field, val, fmt.Sprintf("converter returned %T", out))
+			return true
+		}
+		field.Set(outVal)
+		return true
+	}
+	return false
+}
+
+// convertibleTo returns rVal as a value of typ: itself for that type, or a number converted without
+// loss to a numeric typ
+func convertibleTo(rVal reflect.Value, typ reflect.Type) (reflect.Value, bool) {
+	if rVal.Type() == typ {
+		return rVal, true
+	}
+	if num, ok := rVal.Interface().(json.Number); ok && isNumber(typ.Kind()) {
+		return numberValue(num, typ)
+	}
+	if !isNumber(rVal.Kind()) || !isNumber(typ.Kind()) {
+		return reflect.Value{}, false
+	}
+	converted := rVal.Convert(typ)
+	if !converted.Convert(rVal.Type()).Equal(rVal) {
+		return reflect.Value{}, false
+	}
+	return converted, true
+}
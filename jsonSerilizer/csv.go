@@ -0,0 +1,189 @@
+package jsonserilizer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+)
+
+// csvColumn is a column of a CSV document, path holds the keys leading to its value ex: [Home City]
+type csvColumn struct {
+	path []string
+}
+
+// MarshalCSV writes a slice of structs, or of pointers to structs, as CSV with a header row. Headers
+// are the keys of Serialize, nested structs are flattened with dotted headers ex: Home.City, and
+// slices and maps are written as JSON text. Nil values and nil elements leave their cells empty.
+func MarshalCSV(v interface{}, opts ...Option) ([]byte, error) {
+	rows := reflect.ValueOf(v)
+	for rows.Kind() == reflect.Pointer && !rows.IsNil() {
+		rows = rows.Elem()
+	}
+	if rows.Kind() != reflect.Slice && rows.Kind() != reflect.Array || !isSquashable(rows.Type().Elem()) {
+		return nil, fmt.Errorf("MarshalCSV requires a slice of structs, got %T", v)
+	}
+
+	o := newOptions(opts)
+	columns := o.csvColumns(structType(rows.Type().Elem()), nil, nil)
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.Join(column.path, ".")
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		var r Result
+		if row.Kind() != reflect.Pointer || !row.IsNil() {
+			r = o.serializeStruct(reflect.Indirect(row))
+		}
+		record := make([]string, len(columns))
+		for j, column := range columns {
+			cell, err := csvCell(column.lookup(r))
+			if err != nil {
+				return nil, fmt.Errorf("MarshalCSV: row %d, column %s: %w", i, header[j], err)
+			}
+			record[j] = cell
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	if o.cycles.err != nil {
+		return nil, fmt.Errorf("MarshalCSV: %w", o.cycles.err)
+	}
+	w.Flush()
+	return out.Bytes(), w.Error()
+}
+
+// UnmarshalCSV reads CSV with a header row into the slice of structs, or of pointers to structs,
+// out points to. Cells are converted to the type of their field like WithStringCoercion, dotted
+// headers fill nested structs and empty cells leave their field unset. Rows that cannot be fully
+// stored are still appended, their errors are returned joined and prefixed with their line.
+func UnmarshalCSV(data []byte, out interface{}, opts ...Option) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Pointer || slice.IsNil() || slice.Elem().Kind() != reflect.Slice || !isSquashable(slice.Elem().Type().Elem()) {
+		return fmt.Errorf("UnmarshalCSV requires a non nil pointer to a slice of structs, got %T", out)
+	}
+	slice = slice.Elem()
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	columns := make([]csvColumn, len(records[0]))
+	for i, header := range records[0] {
+		columns[i] = csvColumn{path: strings.Split(header, ".")}
+	}
+
+	opts = append(opts, func(o *options) {
+		o.csv = true
+		o.stringCoercion = true
+	})
+	elemType := slice.Type().Elem()
+	var errs []error
+	for i, record := range records[1:] {
+		r := make(Result)
+		for j, cell := range record {
+			if cell != "" {
+				setPath(r, columns[j].path, cell)
+			}
+		}
+		elem := reflect.New(structType(elemType))
+		if err := Deserialize(r, elem.Interface(), opts...); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", i+2, err))
+		}
+		if elemType.Kind() != reflect.Pointer {
+			elem = elem.Elem()
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+	return errors.Join(errs...)
+}
+
+// structType returns typ, or the type it points to
+func structType(typ reflect.Type) reflect.Type {
+	return structwalk.StructType(typ)
+}
+
+// csvColumns lists the columns of typ in field order, nested structs are flattened below prefix.
+// parents holds the struct types being flattened, a type nested in itself gets a single column.
+func (o options) csvColumns(typ reflect.Type, prefix []string, parents []reflect.Type) []csvColumn {
+	parents = append(parents, typ)
+	var columns []csvColumn
+	for _, field := range structFields(typ) {
+		info := o.fieldInfo(field)
+		path := append(append([]string(nil), prefix...), info.key)
+		switch {
+		case info.skip, info.remain:
+		case info.squash:
+			columns = append(columns, o.csvColumns(structType(field.Type), prefix, parents)...)
+		case isNestedStruct(field.Type, parents):
+			columns = append(columns, o.csvColumns(structType(field.Type), path, parents)...)
+		default:
+			columns = append(columns, csvColumn{path: path})
+		}
+	}
+	return columns
+}
+
+// lookup returns the value of the column in r, nil when a key of its path is missing
+func (c csvColumn) lookup(r map[string]interface{}) interface{} {
+	var val interface{} = r
+	for _, key := range c.path {
+		m, ok := asMap(val)
+		if !ok {
+			return nil
+		}
+		val = m[key]
+	}
+	return val
+}
+
+// csvCell returns the text of a value, lists and maps as JSON
+func csvCell(val interface{}) (string, error) {
+	switch v := plainValue(val).(type) {
+	case nil:
+		return "", nil
+	case []interface{}, map[string]interface{}:
+		data, err := json.Marshal(v)
+		return string(data), err
+	default:
+		return textValue(v), nil
+	}
+}
+
+// csvValue decodes the JSON text of a cell holding a list or a map for slice, array and map fields
+func (d *decoder) csvValue(field reflect.Value, val interface{}) interface{} {
+	text, ok := val.(string)
+	if !ok {
+		return val
+	}
+	switch field.Kind() {
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.Uint8 {
+			return val
+		}
+	case reflect.Array, reflect.Map:
+	default:
+		return val
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+		return val
+	}
+	return decoded
+}
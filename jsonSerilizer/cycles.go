@@ -0,0 +1,94 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CycleError reports a pointer leading back to a struct being serialized ex: a tree node pointing to
+// its parent. Serialize breaks such cycles with nil, Marshal and the other calls returning an error
+// report them.
+type CycleError struct {
+	// Path is the key path of the pointer closing the cycle ex: Children[0].Parent
+	Path string
+	// Type is the type of the pointer ex: *tree.Node
+	Type string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("circular reference at %s (%s)", e.Path, e.Type)
+}
+
+// pointerKey identifies a pointer by address and type, since a struct and its first field share
+// their address
+type pointerKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// cycleState tracks the pointers to the structs being serialized by a call, from the root to the
+// current value, shared pointers which do not form a cycle are serialized every time
+type cycleState struct {
+	active map[pointerKey]bool
+	// path holds the keys and indexes leading to the current value, for CycleError
+	path []pathElem
+	// err is the first cycle met
+	err *CycleError
+}
+
+// pathElem is a step of a key path: a key, a slice index or a map key
+type pathElem struct {
+	key    string
+	index  int
+	mapKey bool
+}
+
+func (c *cycleState) push(elem pathElem) {
+	c.path = append(c.path, elem)
+}
+
+func (c *cycleState) pop() {
+	c.path = c.path[:len(c.path)-1]
+}
+
+// enter marks the struct ptr points to as being serialized, ok is false when it already is, the
+// cycle is then recorded
+func (c *cycleState) enter(ptr reflect.Value) (ok bool) {
+	key := pointerKey{addr: ptr.Pointer(), typ: ptr.Type()}
+	if c.active[key] {
+		if c.err == nil {
+			c.err = &CycleError{Path: c.pathString(), Type: ptr.Type().String()}
+		}
+		return false
+	}
+	if c.active == nil {
+		c.active = make(map[pointerKey]bool)
+	}
+	c.active[key] = true
+	return true
+}
+
+// leave ends the serialization of the struct ptr points to
+func (c *cycleState) leave(ptr reflect.Value) {
+	delete(c.active, pointerKey{addr: ptr.Pointer(), typ: ptr.Type()})
+}
+
+// pathString formats the current path ex: Children[0].Parent
+func (c *cycleState) pathString() string {
+	var out strings.Builder
+	for _, elem := range c.path {
+		switch {
+		case elem.mapKey:
+			fmt.Fprintf(&out, "[%s]", elem.key)
+		case elem.key == "":
+			fmt.Fprintf(&out, "[%d]", elem.index)
+		default:
+			if out.Len() > 0 {
+				out.WriteByte('.')
+			}
+			out.WriteString(elem.key)
+		}
+	}
+	return out.String()
+}
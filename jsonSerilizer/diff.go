@@ -0,0 +1,79 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff returns the fields whose value differs between before and after, keyed by their dotted path
+// ex: Address.City and holding the value of after, for audit logs and PATCH payloads. Nested structs
+// and maps are compared key by key, slices as a whole, and a field left out of after ex: omitempty
+// is reported with a nil value. before and after are structs, or pointers to structs, and opts apply
+// to both like in Serialize.
+func Diff(before, after interface{}, opts ...Option) Result {
+	changes := make(Result)
+	diffMaps(changes, "", diffSide(before, opts), diffSide(after, opts))
+	return changes
+}
+
+// diffSide serializes a struct, or the struct a pointer points to, nil pointers give an empty map
+func diffSide(v interface{}, opts []Option) map[string]interface{} {
+	rVal := reflect.ValueOf(v)
+	for rVal.Kind() == reflect.Pointer && !rVal.IsNil() {
+		rVal = rVal.Elem()
+	}
+	if rVal.Kind() != reflect.Struct {
+		return nil
+	}
+	return Serialize(rVal.Interface(), opts...)
+}
+
+// diffMaps records into changes the keys of before and after whose values differ, below prefix
+func diffMaps(changes Result, prefix string, before, after map[string]interface{}) {
+	keys := make(map[string]bool, len(after))
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	for key := range keys {
+		path := fieldPath(prefix, key)
+		beforeVal, inBefore := before[key]
+		afterVal, inAfter := after[key]
+		beforeMap, beforeIsMap := asMap(beforeVal)
+		afterMap, afterIsMap := asMap(afterVal)
+		switch {
+		case beforeIsMap && afterIsMap:
+			diffMaps(changes, path, beforeMap, afterMap)
+		case inBefore != inAfter || !reflect.DeepEqual(beforeVal, afterVal):
+			changes[path] = afterVal
+		}
+	}
+}
+
+// Patch applies a partial document to the struct dst points to, like a JSON merge patch: fields whose
+// key is missing are left untouched, their default tags are not applied, nested objects are merged
+// into the current structs, pointers and maps, null clears a field like in Deserialize or removes a
+// map entry. It returns the dotted paths of the fields whose value changed ex: [Address.City Age],
+// sorted, along with the errors of Deserialize.
+func Patch(r Result, dst interface{}, opts ...Option) ([]string, error) {
+	rVal := reflect.ValueOf(dst)
+	if rVal.Kind() != reflect.Pointer || rVal.IsNil() || rVal.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("Patch requires a non nil pointer to a struct, got %T", dst)
+	}
+	before := Serialize(rVal.Elem().Interface(), opts...)
+	err := Deserialize(r, dst, append(opts, func(o *options) {
+		o.patch = true
+	})...)
+
+	changes := make(Result)
+	diffMaps(changes, "", before, Serialize(rVal.Elem().Interface(), opts...))
+	changed := make([]string, 0, len(changes))
+	for path := range changes {
+		changed = append(changed, path)
+	}
+	sort.Strings(changed)
+	return changed, err
+}
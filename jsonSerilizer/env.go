@@ -0,0 +1,85 @@
+package jsonserilizer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// DeserializeEnv populates a config struct from environment variables named after prefix and the
+// keys of its fields in upper snake case ex: APP_DB_HOST for the Host field of the DB field with
+// prefix APP, or DB_HOST without prefix. Values are converted like WithStringCoercion, slices are
+// read from comma separated lists ex: APP_HOSTS=a,b and default tags apply to the variables that
+// are unset or empty. WithRequiredKeys reports the missing variables by name.
+func DeserializeEnv(prefix string, refOut interface{}, opts ...Option) error {
+	rVal := reflect.ValueOf(refOut)
+	if rVal.Kind() != reflect.Pointer || rVal.IsNil() || rVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("DeserializeEnv requires a non nil pointer to a struct, got %T", refOut)
+	}
+	prefix = strings.TrimSuffix(prefix, "_")
+	o := newOptions(opts)
+	r := o.envResult(rVal.Elem().Type(), prefix, nil, os.LookupEnv)
+	err := Deserialize(r, refOut, append(opts, WithStringCoercion())...)
+
+	// report the variables to set rather than key paths ex: APP_DB_HOST for db.host
+	var missing *MissingKeysError
+	if errors.As(err, &missing) {
+		for i, keyPath := range missing.Keys {
+			name := prefix
+			for _, key := range strings.Split(keyPath, ".") {
+				name = envName(name, key)
+			}
+			missing.Keys[i] = name
+		}
+	}
+	return err
+}
+
+// envResult collects the variables of the fields of typ, named below prefix, into a Result.
+// parents holds the struct types being read, see isNestedStruct.
+func (o options) envResult(typ reflect.Type, prefix string, parents []reflect.Type, lookup func(string) (string, bool)) Result {
+	parents = append(parents, typ)
+	r := make(Result)
+	for _, field := range structFields(typ) {
+		info := o.fieldInfo(field)
+		name := envName(prefix, info.key)
+		switch {
+		case info.skip, info.remain:
+		case info.squash:
+			for key, val := range o.envResult(structType(field.Type), prefix, parents, lookup) {
+				if _, ok := r[key]; !ok {
+					r[key] = val
+				}
+			}
+		case isNestedStruct(field.Type, parents):
+			if nested := o.envResult(structType(field.Type), name, parents, lookup); len(nested) > 0 {
+				r[info.key] = nested
+			}
+		default:
+			text, ok := lookup(name)
+			if !ok || text == "" {
+				continue
+			}
+			r[info.key] = text
+			if kind := field.Type.Kind(); kind == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 || kind == reflect.Array {
+				var elems []interface{}
+				for _, elem := range strings.Split(text, ",") {
+					elems = append(elems, strings.TrimSpace(elem))
+				}
+				r[info.key] = elems
+			}
+		}
+	}
+	return r
+}
+
+// envName returns the variable of key below prefix ex: APP_MAX_CONNS for maxConns
+func envName(prefix, key string) string {
+	name := strings.ToUpper(strings.ReplaceAll(SnakeCase(key), "-", "_"))
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
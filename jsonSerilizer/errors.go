@@ -0,0 +1,75 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldTypeError reports a value Deserialize could not store in a field
+type FieldTypeError struct {
+	// Field is the path of the field ex: Home.Zip, Tags[1] or Scores[alice]
+	Field string
+	// Expected is the Go type of the field ex: int
+	Expected string
+	// Got is the type of the value ex: string
+	Got string
+	// Reason details why a value of a convertible type was refused ex: 2.5 does not fit
+	Reason string
+}
+
+func (e *FieldTypeError) Error() string {
+	msg := fmt.Sprintf("field %s: expected %s, got %s", e.Field, e.Expected, e.Got)
+	if e.Reason != "" {
+		msg += ": " + e.Reason
+	}
+	return msg
+}
+
+// FieldTypeErrors is returned by Deserialize with every field that could not be stored,
+// the other fields are populated
+type FieldTypeErrors []*FieldTypeError
+
+func (fe FieldTypeErrors) Error() string {
+	msgs := make([]string, len(fe))
+	for i, err := range fe {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// UnknownKeysError is returned WithStrict for keys no field matches
+type UnknownKeysError struct {
+	// Keys are the paths of the keys in sorted order ex: emial, Home.zipcode
+	Keys []string
+}
+
+func (e *UnknownKeysError) Error() string {
+	return "unknown keys: " + strings.Join(e.Keys, ", ")
+}
+
+// MissingKeysError is returned WithRequiredKeys for missing keys tagged required
+type MissingKeysError struct {
+	// Keys are the paths of the keys in sorted order ex: port, Database.host
+	Keys []string
+}
+
+func (e *MissingKeysError) Error() string {
+	return "missing required keys: " + strings.Join(e.Keys, ", ")
+}
+
+// fieldPath joins a field name to the path of its parent
+func fieldPath(prefix, fieldName string) string {
+	if prefix == "" {
+		return fieldName
+	}
+	return prefix + "." + fieldName
+}
+
+// typeName describes the type of a decoded value for errors
+func typeName(val interface{}) string {
+	if val == nil {
+		return "null"
+	}
+	return reflect.TypeOf(val).String()
+}
@@ -0,0 +1,32 @@
+package jsonserilizer
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+)
+
+// cachedField is a struct field with what its tags say, as parsed by parseField
+type cachedField struct {
+	structwalk.Field
+	info fieldInfo
+}
+
+// fieldCache holds the []cachedField of every struct type walked, see structFields
+var fieldCache sync.Map
+
+// structFields returns the fields of the struct type typ in order, their tags parsed once per type
+// rather than on every Serialize and Deserialize call
+func structFields(typ reflect.Type) []cachedField {
+	if fields, ok := fieldCache.Load(typ); ok {
+		return fields.([]cachedField)
+	}
+	walked := structwalk.Fields(typ)
+	fields := make([]cachedField, len(walked))
+	for i, field := range walked {
+		fields[i] = cachedField{Field: field, info: parseField(field)}
+	}
+	cached, _ := fieldCache.LoadOrStore(typ, fields)
+	return cached.([]cachedField)
+}
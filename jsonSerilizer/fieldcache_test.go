@@ -0,0 +1,52 @@
+package jsonserilizer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+)
+
+type cachedAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city" default:"Cairo"`
+}
+
+type cachedUser struct {
+	ID       int64             `json:"id"`
+	Name     string            `json:"name"`
+	Email    string            `json:"email,omitempty"`
+	Password string            `json:"-"`
+	Created  time.Time         `json:"created" time_format:"2006-01-02"`
+	Tags     []string          `json:"tags" aliases:"labels"`
+	Labels   map[string]string `json:"labels_map"`
+	Address  cachedAddress     `json:"address"`
+}
+
+// uncachedStructFields parses the fields of typ like structFields does on a cache miss
+func uncachedStructFields(typ reflect.Type) []cachedField {
+	walked := structwalk.Fields(typ)
+	fields := make([]cachedField, len(walked))
+	for i, field := range walked {
+		fields[i] = cachedField{Field: field, info: parseField(field)}
+	}
+	return fields
+}
+
+func BenchmarkStructFields(b *testing.B) {
+	typ := reflect.TypeOf(cachedUser{})
+	b.Run("cached", func(b *testing.B) {
+		structFields(typ)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			structFields(typ)
+		}
+	})
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			uncachedStructFields(typ)
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package jsonserilizer
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SerializeFlat converts a struct like Serialize into a single level map keyed by dotted paths ex:
+// {"Address.City": "Cairo", "Tags.0": "a"}, for form encodings, environment mappings and diff
+// displays. Nested structs and maps are flattened by key and slices by index, empty ones are kept
+// as they are under their own key. Map keys holding a dot cannot be told apart from nested keys.
+func SerializeFlat(s interface{}, opts ...Option) map[string]interface{} {
+	r := Serialize(s, opts...)
+	if r == nil {
+		return nil
+	}
+	flat := make(map[string]interface{})
+	flatten(flat, "", plainValue(r))
+	return flat
+}
+
+// flatten stores val into flat under key, or the entries of maps and lists below it
+func flatten(flat map[string]interface{}, key string, val interface{}) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 || key == "" {
+			for nestedKey, elem := range v {
+				flatten(flat, fieldPath(key, nestedKey), elem)
+			}
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for i, elem := range v {
+				flatten(flat, fieldPath(key, strconv.Itoa(i)), elem)
+			}
+			return
+		}
+	}
+	flat[key] = val
+}
+
+// DeserializeFlat populates a struct from the dotted paths of SerializeFlat, nested structs and
+// maps are filled by key and slices and arrays by index. Indexes must run from 0 without gaps. The
+// keys are applied in sorted order, so a key ex: Address set along with Address.City is replaced by
+// the nested one.
+func DeserializeFlat(flat map[string]interface{}, refOut interface{}, opts ...Option) error {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	r := make(Result)
+	for _, key := range keys {
+		setPath(r, strings.Split(key, "."), flat[key])
+	}
+	return Deserialize(r, refOut, append(opts, func(o *options) {
+		o.flat = true
+	})...)
+}
+
+// flatValue turns the map of indexes a flattened list is read back as into a list for slice and
+// array fields, maps with other keys are left for Deserialize to report
+func flatValue(field reflect.Value, val interface{}) interface{} {
+	m, ok := val.(map[string]interface{})
+	if !ok || field.Kind() != reflect.Slice && field.Kind() != reflect.Array {
+		return val
+	}
+	elems := make([]interface{}, len(m))
+	for key, elem := range m {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(elems) || strconv.Itoa(i) != key {
+			return val
+		}
+		elems[i] = elem
+	}
+	return elems
+}
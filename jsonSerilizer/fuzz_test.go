@@ -0,0 +1,123 @@
+package jsonserilizer
+
+import (
+	"testing"
+	"time"
+)
+
+// The shapes Deserialize is fuzzed against, covering scalars, nesting, collections, tags and
+// recursive types
+
+type fuzzScalars struct {
+	Name    string
+	Age     int8
+	Count   uint16
+	Ratio   float32
+	Active  bool
+	Payload []byte
+	Any     interface{}
+}
+
+type fuzzNested struct {
+	Owner   fuzzScalars
+	Manager *fuzzScalars
+	Tags    []string
+	Scores  map[string]float64
+	Matrix  [][]int
+	Pair    [2]uint8
+	Items   []fuzzScalars
+	ByID    map[string]*fuzzScalars
+}
+
+type fuzzTagged struct {
+	ID      int64             `json:"id,required"`
+	Created time.Time         `json:"created" time_format:"2006-01-02"`
+	Timeout time.Duration     `json:"timeout" default:"5s"`
+	Port    int               `json:"port" default:"8080"`
+	Key     string            `json:"key" aliases:"api_key,token"`
+	Secret  string            `json:"-"`
+	Base    fuzzScalars       `json:",squash"`
+	Extra   map[string]string `json:",remain"`
+}
+
+type fuzzList struct {
+	Value int
+	Next  *fuzzList
+}
+
+// fuzzDecodeSeeds are documents holding every shape, along with malformed and mistyped ones
+var fuzzDecodeSeeds = []string{
+	`{}`,
+	`{"Name":"Ada","Age":36,"Count":7,"Ratio":0.5,"Active":true,"Payload":"aGk=","Any":[1,"a",null]}`,
+	`{"Owner":{"Name":"x"},"Manager":{"Age":300},"Tags":["a",1,null],"Scores":{"a":1.5,"b":"x"},"Matrix":[[1],[2,3]],"Pair":[1,2,3],"Items":[{},null,"a"],"ByID":{"a":null,"b":{"Name":"y"}}}`,
+	`{"id":"12","created":"2024-02-30","timeout":"x","port":"80","token":"t","Name":"n","other":{"a":1}}`,
+	`{"Value":1,"Next":{"Value":2,"Next":{"Value":3,"Next":null}}}`,
+	`{"Age":-129,"Count":-1,"Ratio":1e300,"Active":"yes","Payload":12}`,
+	`{"Owner":[],"Manager":1,"Tags":{},"Scores":[],"Matrix":"x","Pair":null}`,
+}
+
+func FuzzDeserialize(f *testing.F) {
+	for _, seed := range fuzzDecodeSeeds {
+		f.Add([]byte(seed))
+	}
+	options := [][]Option{
+		nil,
+		{WithStrict(), WithRequiredKeys()},
+		{WithWeaklyTypedInput(), WithCaseInsensitiveKeys()},
+		{WithStringCoercion(), WithUseNumber()},
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := JSON.Decode(data)
+		if err != nil {
+			return
+		}
+		for _, opts := range options {
+			// errors are expected, panics are not
+			_ = Deserialize(r, &fuzzScalars{}, opts...)
+			_ = Deserialize(r, &fuzzNested{}, opts...)
+			_ = Deserialize(r, &fuzzTagged{}, opts...)
+			_ = Deserialize(r, &fuzzList{}, opts...)
+		}
+	})
+}
+
+// fuzzCodec checks that codec decodes any data without panicking, and encodes what it decoded
+func fuzzCodec(f *testing.F, codec Codec) {
+	for _, seed := range fuzzDecodeSeeds {
+		r, err := JSON.Decode([]byte(seed))
+		if err != nil {
+			f.Fatal(err)
+		}
+		if data, err := codec.Encode(r); err == nil {
+			f.Add(data)
+		}
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := codec.Decode(data)
+		if err != nil {
+			return
+		}
+		if _, err := codec.Encode(r); err != nil {
+			// a document decoded by a codec is not always one it can write ex: mixed TOML arrays
+			t.Logf("Encode() error = %v", err)
+		}
+		_ = Deserialize(r, &fuzzNested{}, codecOptions(codec, nil)...)
+	})
+}
+
+func FuzzJSONDecode(f *testing.F) {
+	fuzzCodec(f, JSON)
+}
+
+func FuzzTOMLDecode(f *testing.F) {
+	fuzzCodec(f, TOML)
+}
+
+func FuzzMsgPackDecode(f *testing.F) {
+	fuzzCodec(f, MsgPack)
+}
+
+func FuzzXMLDecode(f *testing.F) {
+	fuzzCodec(f, XML)
+}
@@ -0,0 +1,47 @@
+package jsonserilizer
+
+import "strings"
+
+// groupsTag lists the views a field belongs to ex: `serialize_groups:"public,admin"`
+const groupsTag = "serialize_groups"
+
+// WithGroups limits the call to the fields of groups, tagged serialize_groups, so one struct can
+// give several views ex: public and admin without a DTO per view. Fields without the tag belong to
+// every view, like the groups tag of the validator. Deserialize ignores the keys of the other
+// fields, so clients cannot set them.
+func WithGroups(groups ...string) Option {
+	return func(o *options) {
+		o.groups = append(o.groups, groups...)
+	}
+}
+
+// SerializeGroup is Serialize limited to the fields of group ex: SerializeGroup(user, "public")
+func SerializeGroup(v interface{}, group string, opts ...Option) Result {
+	return Serialize(v, append(opts, WithGroups(group))...)
+}
+
+// parseGroups reads the serialize_groups tag of a field
+func parseGroups(tag string) []string {
+	var groups []string
+	for _, group := range strings.Split(tag, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// inGroups reports whether a field of groups is part of the call, see WithGroups
+func (o options) inGroups(groups []string) bool {
+	if len(o.groups) == 0 || len(groups) == 0 {
+		return true
+	}
+	for _, group := range groups {
+		for _, active := range o.groups {
+			if group == active {
+				return true
+			}
+		}
+	}
+	return false
+}
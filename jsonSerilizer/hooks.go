@@ -0,0 +1,95 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DecodeHookFunc rewrites data, a value decoded into a Result, before Deserialize stores it into a
+// value of type to, ex: to split a comma separated string for a slice field. The returned value is
+// then stored as usual, an error is reported as a FieldTypeError.
+type DecodeHookFunc func(from, to reflect.Type, data interface{}) (interface{}, error)
+
+// WithDecodeHook runs hooks, in order, before every value Deserialize stores: fields, elements of
+// slices and maps, and whole objects for struct fields. Null values do not run the hooks. Unlike
+// RegisterConverter, hooks apply to a single call and may return values of any type.
+func WithDecodeHook(hooks ...DecodeHookFunc) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// runHooks returns val rewritten by the decode hooks for field, ok is false when a hook failed
+func (d *decoder) runHooks(field reflect.Value, val interface{}, path string) (out interface{}, ok bool) {
+	for _, hook := range d.hooks {
+		if val == nil {
+			return nil, true
+		}
+		rewritten, err := hook(reflect.TypeOf(val), field.Type(), val)
+		if err != nil {
+			d.fail(path, field, val, err.Error())
+			return nil, false
+		}
+		val = rewritten
+	}
+	return val, true
+}
+
+// StringToSliceHook splits strings on sep for slice fields ex: "a,b" into []string{"a", "b"},
+// trimming the spaces around each element. []byte fields are left alone, they read base64.
+func StringToSliceHook(sep string) DecodeHookFunc {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		text, ok := data.(string)
+		if !ok || to.Kind() != reflect.Slice || to.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+		if text == "" {
+			return []interface{}{}, nil
+		}
+		parts := strings.Split(text, sep)
+		elems := make([]interface{}, len(parts))
+		for i, part := range parts {
+			elems[i] = strings.TrimSpace(part)
+		}
+		return elems, nil
+	}
+}
+
+// EpochToTimeHook reads numbers as Unix times in seconds for time.Time fields ex: 1700000000,
+// fractions giving the nanoseconds ex: 1700000000.5
+func EpochToTimeHook() DecodeHookFunc {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != timeType {
+			return data, nil
+		}
+		var seconds float64
+		switch v := data.(type) {
+		case json.Number:
+			if n, err := v.Int64(); err == nil {
+				return time.Unix(n, 0).UTC(), nil
+			}
+			f, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			seconds = f
+		default:
+			rVal := reflect.ValueOf(data)
+			switch {
+			case rVal.CanInt():
+				return time.Unix(rVal.Int(), 0).UTC(), nil
+			case rVal.CanUint():
+				return time.Unix(int64(rVal.Uint()), 0).UTC(), nil
+			case rVal.CanFloat():
+				seconds = rVal.Float()
+			default:
+				return data, nil
+			}
+		}
+		whole, fraction := math.Modf(seconds)
+		return time.Unix(int64(whole), int64(fraction*1e9)).UTC(), nil
+	}
+}
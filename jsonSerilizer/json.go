@@ -2,44 +2,186 @@
 package jsonserilizer
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"time"
 )
 
 // Result is a map type used for serialization/deserialization.
 type Result map[string]interface{}
 
-// Serialize converts a struct into a Result map.
-func Serialize(s interface{}) Result {
-	result := make(Result)
+// Serialize converts a struct into a Result map, nil for other values.
+// Zero value fields are left out when tagged omitempty, or all of them WithOmitEmpty.
+// Keys are the names of the json struct tags, or the field names for fields without one.
+// Nested structs, and pointers to structs, become nested Result maps, slices and arrays []interface{}
+// and maps with string or integer keys map[string]interface{}. A pointer leading back to a struct
+// being serialized ex: a child pointing to its parent becomes nil, see CycleError.
+func Serialize(s interface{}, opts ...Option) Result {
 	// assume s will be a struct
 	rTyp := reflect.TypeOf(s)
 	rVal := reflect.ValueOf(s)
 
 	// Ensure the input is a struct.
-	if rTyp.Kind() != reflect.Struct {
+	if rTyp == nil || rTyp.Kind() != reflect.Struct {
 		return nil
 	}
+	return newOptions(opts).serializeStruct(rVal)
+}
+
+// serializeStruct converts the fields of a struct value into a Result map
+func (o options) serializeStruct(rVal reflect.Value) Result {
+	rTyp := rVal.Type()
+	var result Result
+	if fields, ok := o.scalarFields(rTyp); ok {
+		result = serializeScalars(rVal, fields, o.omitEmpty)
+	} else {
+		result = o.serializeFields(rVal)
+	}
+	if o.orders != nil {
+		o.orders[reflect.ValueOf(result).Pointer()] = orderedKeys{result: result, keys: o.fieldOrder(rTyp, result, nil)}
+	}
+	if o.xml {
+		o.annotateXML(result, rVal)
+	}
+	return result
+}
+
+// serializeFields converts the fields of a struct value one by one, nested values included
+func (o options) serializeFields(rVal reflect.Value) Result {
+	result := make(Result)
+	rTyp := rVal.Type()
+	var remain reflect.Value
 
 	// Iterate over the struct fields and set them in the map.
 	//inspecting fileds and set a new map
-	for i := 0; i < rVal.NumField(); i++ {
+	for i, field := range structFields(rTyp) {
 		// get prop | attribute with name and type
-		field := rTyp.Field(i)
 		filedValue := rVal.Field(i)
+		info := o.fieldInfo(field)
+		if info.skip || (info.omitEmpty || o.omitEmpty) && filedValue.IsZero() {
+			continue
+		}
+		if info.squash {
+			o.squashInto(result, filedValue)
+			continue
+		}
+		if info.remain {
+			remain = filedValue
+			continue
+		}
 		//  convert it to interface{}
-		result[field.Name] = filedValue.Interface()
+		fieldOpts := o
+		fieldOpts.timeLayout = info.timeFormat
+		o.cycles.push(pathElem{key: info.key})
+		result[info.key] = fieldOpts.serializeValue(filedValue)
+		if info.mask != "" {
+			result[info.key] = maskValue(result[info.key], info.mask)
+		}
+		o.cycles.pop()
+	}
+	if remain.IsValid() {
+		o.remainInto(result, remain)
 	}
 	return result
 }
 
-// Deserialize populates a struct from a Result map.
-func Deserialize(r Result, refOut interface{}) error {
+// squashInto merges the fields of a struct, or of the struct a pointer points to, into result.
+// Keys already set by the parent are kept, the parent fields win like promoted fields in Go.
+func (o options) squashInto(result Result, val reflect.Value) {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() || !o.cycles.enter(val) {
+			return
+		}
+		defer o.cycles.leave(val)
+		val = val.Elem()
+	}
+	for key, fieldVal := range o.serializeStruct(val) {
+		if _, ok := result[key]; !ok {
+			result[key] = fieldVal
+		}
+	}
+}
+
+// serializeValue converts a field value, structs are converted recursively and nil pointers become nil.
+// Types implementing ResultMarshaler choose their value, time.Time values are formatted with the
+// layout of the field and json.Marshaler implementations are stored as the JSON they produce.
+func (o options) serializeValue(val reflect.Value) interface{} {
+	if val.Kind() == reflect.Pointer && val.IsNil() {
+		return nil
+	}
+	if out, ok := marshalResult(val); ok {
+		return out
+	}
+	if o.bson && (val.Type() == timeType || val.Kind() == reflect.Pointer && val.Type().Elem() == timeType) {
+		return reflect.Indirect(val).Interface()
+	}
+	if val.Type() == timeType {
+		return val.Interface().(time.Time).Format(o.layout())
+	}
+	if val.Kind() == reflect.Pointer && val.Type().Elem() == timeType {
+		return val.Elem().Interface().(time.Time).Format(o.layout())
+	}
+	if out, ok := marshalJSON(val); ok {
+		return out
+	}
+	switch val.Kind() {
+	case reflect.Pointer:
+		if val.Elem().Kind() == reflect.Struct {
+			if !o.cycles.enter(val) {
+				return nil
+			}
+			defer o.cycles.leave(val)
+			return o.serializeStruct(val.Elem())
+		}
+	case reflect.Struct:
+		return o.serializeStruct(val)
+	case reflect.Slice:
+		// nil slices stay null and []byte is left to encoding/json, which writes it as base64
+		if val.IsNil() {
+			return nil
+		}
+		if val.Type().Elem().Kind() != reflect.Uint8 {
+			return o.serializeSlice(val)
+		}
+	case reflect.Array:
+		return o.serializeSlice(val)
+	case reflect.Map:
+		if val.IsNil() {
+			return nil
+		}
+		if out, ok := o.serializeMap(val); ok {
+			return out
+		}
+	case reflect.Interface:
+		if out, ok := o.serializeTyped(val); ok {
+			return out
+		}
+	}
+	return val.Interface()
+}
+
+// DeserializeStrict is Deserialize WithStrict, keys without a field are reported as an *UnknownKeysError
+func DeserializeStrict(r Result, refOut interface{}, opts ...Option) error {
+	return Deserialize(r, refOut, append(opts, WithStrict())...)
+}
+
+// Deserialize populates a struct from a Result map, looking fields up by the keys of Serialize.
+// Nested maps populate nested structs and maps, lists populate slices and arrays element by element,
+// and pointers are allocated as needed.
+func Deserialize(r Result, refOut interface{}, opts ...Option) error {
 	//  Ensure refOut is a pointer
 	rVal := reflect.ValueOf(refOut)
 	if rVal.Kind() != reflect.Pointer {
 		return fmt.Errorf("refOut must be a pointer !")
 	}
+	if rVal.IsNil() {
+		return fmt.Errorf("refOut must be a non nil pointer !")
+	}
 
 	// Get the type of the struct  ex:Person struct
 	structVal := rVal.Elem()
@@ -47,21 +189,354 @@ func Deserialize(r Result, refOut interface{}) error {
 		return fmt.Errorf("refOut must be a pointer struct !")
 
 	}
+	d := &decoder{options: newOptions(opts)}
+	d.deserializeObject(r, structVal, "")
+	d.fillMetadata()
+	return d.err()
+}
+
+// decoder holds the state of a single Deserialize call
+type decoder struct {
+	options
+	errs FieldTypeErrors
+	// unknown are the paths of the keys no field matched, reported WithStrict
+	unknown []string
+	// missing are the paths of the required keys not found, reported WithRequiredKeys
+	missing []string
+	// usedKeys, unusedKeys and unset are recorded WithMetadata
+	usedKeys, unusedKeys, unset []string
+	// depth is the nesting of the value being stored, see maxDecodeDepth
+	depth int
+}
+
+// maxDecodeDepth bounds the nesting of the values Deserialize stores, so a Result built from
+// untrusted input cannot exhaust the stack through a recursive type ex: a linked list node
+const maxDecodeDepth = 10000
+
+// err returns the errors of the call, the unknown and missing keys joined to the type errors
+func (d *decoder) err() error {
+	var errs []error
+	if len(d.unknown) > 0 {
+		sort.Strings(d.unknown)
+		errs = append(errs, &UnknownKeysError{Keys: d.unknown})
+	}
+	if len(d.missing) > 0 {
+		sort.Strings(d.missing)
+		errs = append(errs, &MissingKeysError{Keys: d.missing})
+	}
+	if len(d.errs) > 0 {
+		errs = append(errs, d.errs)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// deserializeObject populates a struct from a whole object, reporting the keys left over WithStrict
+func (d *decoder) deserializeObject(r map[string]interface{}, structVal reflect.Value, prefix string) {
+	consumed := d.deserializeStruct(r, structVal, prefix)
+	d.setRemain(r, structVal, prefix, consumed)
+	if !d.strict && d.metadata == nil {
+		return
+	}
+	for key := range r {
+		if consumed[key] || d.xml && key == xmlNameKey {
+			continue
+		}
+		if d.strict {
+			d.unknown = append(d.unknown, fieldPath(prefix, key))
+		}
+		if d.metadata != nil {
+			d.unusedKeys = append(d.unusedKeys, fieldPath(prefix, key))
+		}
+	}
+}
+
+// fail records that val could not be stored in field
+func (d *decoder) fail(path string, field reflect.Value, val interface{}, reason string) {
+	d.errs = append(d.errs, &FieldTypeError{Field: path, Expected: field.Type().String(), Got: typeName(val), Reason: reason})
+}
 
+// deserializeStruct sets the fields of structVal found in r, prefix is the path of structVal.
+// consumed holds the keys of r matched by a field.
+func (d *decoder) deserializeStruct(r map[string]interface{}, structVal reflect.Value, prefix string) (consumed map[string]bool) {
+	consumed = make(map[string]bool)
 	// Get the type of the struct
 	structType := structVal.Type()
 
-	for i := 0; i < structType.NumField(); i++ {
+	for i, field := range structFields(structType) {
 		//  acces struct fileds
-		field := structType.Field(i)
+		info := d.fieldInfo(field)
+		if info.skip || info.remain {
+			continue
+		}
+		if info.squash {
+			for key := range d.unsquash(d.promoted(r, structType), structVal.Field(i), fieldPath(prefix, field.Name)) {
+				consumed[key] = true
+			}
+			continue
+		}
+
+		// Check if the field exists in the map, or else under one of its former keys
+		key, val, ok := d.lookup(r, info.key)
+		for _, alias := range info.aliases {
+			aliasKey, aliasVal, found := d.lookup(r, alias)
+			if !found {
+				continue
+			}
+			// keys left by older producers along with the current one are used up too
+			consumed[aliasKey] = true
+			if !ok {
+				key, val, ok = aliasKey, aliasVal, true
+			}
+		}
+		if ok {
+			consumed[key] = true
+			if d.metadata != nil {
+				d.usedKeys = append(d.usedKeys, fieldPath(prefix, key))
+			}
+		} else if !d.patch {
+			// a patch leaves the fields of missing keys untouched, defaults included
+			d.missingKey(info, structVal.Field(i), fieldPath(prefix, info.key), fieldPath(prefix, field.Name))
+		}
+		if ok || info.hasDefault && !d.patch {
+			if !ok {
+				val = info.defaultValue
+			}
+			parentLayout, coercion := d.timeLayout, d.stringCoercion
+			d.timeLayout = info.timeFormat
+			// defaults are written as strings whatever the field type
+			d.stringCoercion = d.stringCoercion || !ok
+			d.setField(structVal.Field(i), val, fieldPath(prefix, field.Name))
+			d.timeLayout, d.stringCoercion = parentLayout, coercion
+		}
+
+	}
+	return consumed
+}
 
-		// Check if the field exists in the map
-		if val, ok := r[field.Name]; ok {
-			structVal.Field(i).Set(reflect.ValueOf(val))
+// missingKey handles a field whose key is missing: a required key is reported WithRequiredKeys, and
+// a nested struct without a default gets the defaults of its own fields
+func (d *decoder) missingKey(info fieldInfo, field reflect.Value, keyPath, path string) {
+	if info.required && d.requiredKeys {
+		d.missing = append(d.missing, keyPath)
+	}
+	if !info.hasDefault && field.Kind() == reflect.Struct && field.Type() != timeType {
+		d.deserializeStruct(nil, field, path)
+		return
+	}
+	if !info.hasDefault && d.metadata != nil {
+		d.unset = append(d.unset, path)
+	}
+}
+
+// lookup returns the key of r matching key and its value, matching keys regardless of case
+// WithCaseInsensitiveKeys when there is no exact match
+func (d *decoder) lookup(r map[string]interface{}, key string) (string, interface{}, bool) {
+	if val, ok := r[key]; ok || !d.caseInsensitive {
+		return key, val, ok
+	}
+	// the first matching key in sorted order keeps the choice stable ex: between NAME and name
+	var match string
+	var val interface{}
+	found := false
+	for candidate, candidateVal := range r {
+		if strings.EqualFold(candidate, key) && (!found || candidate < match) {
+			match, val, found = candidate, candidateVal, true
+		}
+	}
+	return match, val, found
+}
+
+// promoted returns the entries of r left to the squashed fields of structType, without the keys of
+// its own fields which win like in Serialize
+func (d *decoder) promoted(r map[string]interface{}, structType reflect.Type) map[string]interface{} {
+	out := make(map[string]interface{}, len(r))
+	for key, val := range r {
+		out[key] = val
+	}
+	for _, field := range structFields(structType) {
+		info := d.fieldInfo(field)
+		if info.skip || info.squash || info.remain {
+			continue
 		}
+		for key := range out {
+			for _, own := range append([]string{info.key}, info.aliases...) {
+				if key == own || d.caseInsensitive && strings.EqualFold(key, own) {
+					delete(out, key)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// unsquash populates a squashed struct field from the keys of its parent and returns the keys it
+// consumed. A nil pointer is only allocated when r holds one of its fields, and left nil when it
+// cannot be set ex: unexported.
+func (d *decoder) unsquash(r map[string]interface{}, field reflect.Value, path string) map[string]bool {
+	if field.Kind() != reflect.Pointer {
+		return d.deserializeStruct(r, field, path)
+	}
+	if !field.IsNil() {
+		return d.deserializeStruct(r, field.Elem(), path)
+	}
+	if !field.CanSet() {
+		return nil
+	}
+	elem := reflect.New(field.Type().Elem())
+	consumed := d.deserializeStruct(r, elem.Elem(), path)
+	if len(consumed) > 0 {
+		field.Set(elem)
+	}
+	return consumed
+}
+
+// setField assigns val to field, converting numbers decoded from JSON ex: float64 into an int field,
+// and filling structs, slices, arrays and maps element by element. Values that cannot be stored are
+// recorded as a FieldTypeError for path.
+func (d *decoder) setField(field reflect.Value, val interface{}, path string) {
+	if field.Kind() != reflect.Pointer {
+		// pointers are followed to their element, which counts as the level
+		if d.depth == maxDecodeDepth {
+			d.fail(path, field, val, fmt.Sprintf("nested deeper than %d levels", maxDecodeDepth))
+			return
+		}
+		d.depth++
+		defer func() { d.depth-- }()
+	}
+	val = d.formatValue(field, val)
+	val, ok := d.runHooks(field, val, path)
+	if !ok {
+		return
+	}
+	rVal := reflect.ValueOf(val)
+	if !rVal.IsValid() {
+		// null clears pointers, interfaces, maps and slices, other values keep their zero or current value
+		switch field.Kind() {
+		case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+			field.SetZero()
+		default:
+			if d.zeroNulls {
+				field.SetZero()
+			}
+		}
+		return
+	}
+	if d.convert(field, val, path) {
+		return
+	}
+	if d.setTyped(field, val, path) {
+		return
+	}
+	if rVal.Type().AssignableTo(field.Type()) {
+		field.Set(rVal)
+		return
+	}
+	if field.Kind() == reflect.Pointer {
+		if d.patch && !field.IsNil() {
+			d.setField(field.Elem(), val, path)
+			return
+		}
+		elem := reflect.New(field.Type().Elem())
+		d.setField(elem.Elem(), val, path)
+		field.Set(elem)
+		return
+	}
+	if d.unmarshalResult(field, val, path) {
+		return
+	}
+	if field.Type() != timeType && d.unmarshalJSON(field, val, path) {
+		return
+	}
+	if nested, ok := asMap(val); ok {
+		switch field.Kind() {
+		case reflect.Struct:
+			d.deserializeObject(nested, field, path)
+			return
+		case reflect.Map:
+			d.setMap(field, nested, path)
+			return
+		}
+	}
+	if text, ok := val.(string); ok && field.Type() == timeType {
+		d.parseTime(field, text, path)
+		return
+	}
+	if text, ok := val.(string); ok && field.Type() == durationType {
+		// durations are written as text by YAML ex: 1m30s
+		d.parseDuration(field, text, path)
+		return
+	}
+	if text, ok := val.(string); ok && field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8 {
+		// []byte is written as base64 by Marshal
+		data, err := base64.StdEncoding.DecodeString(text)
+		if err != nil {
+			d.fail(path, field, val, "invalid base64")
+			return
+		}
+		field.SetBytes(data)
+		return
+	}
+	if elems, ok := val.([]interface{}); ok && (field.Kind() == reflect.Slice || field.Kind() == reflect.Array) {
+		d.setSlice(field, elems, path)
+		return
+	}
+	if num, ok := val.(json.Number); ok && isNumber(field.Kind()) {
+		d.setNumber(field, num, path)
+		return
+	}
+	if num, ok := val.(json.Number); ok && field.Kind() == reflect.String && !d.stringCoercion {
+		// a number is only stored as text WithStringCoercion, as when decoded as float64
+		d.fail(path, field, num, "")
+		return
+	}
+	if rVal.Kind() == field.Kind() && (rVal.Kind() == reflect.String || rVal.Kind() == reflect.Bool) {
+		// named types ex: type Status string
+		field.Set(rVal.Convert(field.Type()))
+		return
+	}
+	if isNumber(rVal.Kind()) && isNumber(field.Kind()) {
+		// refuse conversions losing the value ex: 2.5 into an int or -1 into a uint
+		if !numberFits(rVal, field) {
+			d.fail(path, field, val, fmt.Sprintf("%v does not fit", val))
+			return
+		}
+		field.Set(rVal.Convert(field.Type()))
+		return
+	}
+	if d.stringCoercion {
+		if ok, err := coerceString(field, rVal); ok {
+			if err != nil {
+				d.fail(path, field, val, err.Error())
+			}
+			return
+		}
+	}
+	d.fail(path, field, val, "")
+}
+
+// asMap returns the object held by val, a Result or a map decoded from JSON
+func asMap(val interface{}) (map[string]interface{}, bool) {
+	switch m := val.(type) {
+	case Result:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	}
+	return nil, false
+}
 
+// isNumber reports whether kind is an integer or floating point kind
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
 	}
-	return nil
+	return false
 }
 
 // Example usage:
@@ -0,0 +1,371 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The operations of a JSON Patch, RFC 6902
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+	OpMove    = "move"
+	OpCopy    = "copy"
+	OpTest    = "test"
+)
+
+// PatchOp is an operation of a JSON Patch, RFC 6902. Path and From are JSON Pointers, RFC 6901, into
+// the keys of Serialize ex: /address/city or /tags/0.
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON writes the value of add, replace and test operations even when it is null
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	switch op.Op {
+	case OpAdd, OpReplace, OpTest:
+		return json.Marshal(struct {
+			Op    string      `json:"op"`
+			Path  string      `json:"path"`
+			Value interface{} `json:"value"`
+		}{op.Op, op.Path, op.Value})
+	}
+	type plain PatchOp
+	return json.Marshal(plain(op))
+}
+
+// GeneratePatch returns the operations turning old into new, for audit trails and for syncing
+// clients that speak JSON Patch. old and new are structs, or pointers to structs, serialized with
+// opts. Objects are compared key by key like Diff, and lists element by element: elements past the
+// end of the shorter list are added or removed, last first, so the indexes stay valid. Keys are
+// visited in sorted order, the same changes always give the same patch.
+func GeneratePatch(old, new interface{}, opts ...Option) []PatchOp {
+	ops := []PatchOp{}
+	return patchMaps(ops, "", diffSide(old, opts), diffSide(new, opts))
+}
+
+// patchMaps appends the operations turning the object before into after, found at path
+func patchMaps(ops []PatchOp, path string, before, after map[string]interface{}) []PatchOp {
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keyPath := path + "/" + escapePointer(key)
+		beforeVal, inBefore := before[key]
+		afterVal, inAfter := after[key]
+		switch {
+		case !inAfter:
+			ops = append(ops, PatchOp{Op: OpRemove, Path: keyPath})
+		case !inBefore:
+			ops = append(ops, PatchOp{Op: OpAdd, Path: keyPath, Value: copyResultValue(afterVal)})
+		default:
+			ops = patchValues(ops, keyPath, beforeVal, afterVal)
+		}
+	}
+	return ops
+}
+
+// patchValues appends the operations turning the value before into after, found at path
+func patchValues(ops []PatchOp, path string, before, after interface{}) []PatchOp {
+	beforeMap, beforeIsMap := asMap(before)
+	afterMap, afterIsMap := asMap(after)
+	if beforeIsMap && afterIsMap {
+		return patchMaps(ops, path, beforeMap, afterMap)
+	}
+	beforeList, beforeIsList := before.([]interface{})
+	afterList, afterIsList := after.([]interface{})
+	if beforeIsList && afterIsList {
+		for i := 0; i < len(beforeList) && i < len(afterList); i++ {
+			ops = patchValues(ops, path+"/"+strconv.Itoa(i), beforeList[i], afterList[i])
+		}
+		for i := len(beforeList) - 1; i >= len(afterList); i-- {
+			ops = append(ops, PatchOp{Op: OpRemove, Path: path + "/" + strconv.Itoa(i)})
+		}
+		for i := len(beforeList); i < len(afterList); i++ {
+			ops = append(ops, PatchOp{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), Value: copyResultValue(afterList[i])})
+		}
+		return ops
+	}
+	if !reflect.DeepEqual(before, after) {
+		ops = append(ops, PatchOp{Op: OpReplace, Path: path, Value: copyResultValue(after)})
+	}
+	return ops
+}
+
+// ApplyPatch applies ops to the struct dst points to, as RFC 6902 describes: the operations apply
+// in order to the keys of Serialize, and a failing one, a test included, leaves dst untouched. The
+// keys the patch changed are then stored like Patch does, removed keys clearing their field or map
+// entry, so fields the patch did not reach ex: skipped or masked ones keep their value and their
+// default tags are not applied. The errors of Deserialize are returned.
+func ApplyPatch(dst interface{}, ops []PatchOp, opts ...Option) error {
+	rVal := reflect.ValueOf(dst)
+	if rVal.Kind() != reflect.Pointer || rVal.IsNil() || rVal.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("ApplyPatch requires a non nil pointer to a struct, got %T", dst)
+	}
+	before := copyResultValue(Serialize(rVal.Elem().Interface(), opts...)).(map[string]interface{})
+	var doc interface{} = copyResultValue(before)
+	for i, op := range ops {
+		var err error
+		if doc, err = applyOp(doc, op); err != nil {
+			return fmt.Errorf("ApplyPatch: operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	after, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("ApplyPatch: the patched document is %s, not an object", typeName(doc))
+	}
+	// removed keys clear their field whatever its kind
+	_, err := Patch(mergePatch(before, after), dst, append(opts[:len(opts):len(opts)], func(o *options) {
+		o.zeroNulls = true
+	})...)
+	return err
+}
+
+// mergePatch returns the merge patch turning the object before into after: the changed keys, with
+// null for the removed ones and changed objects holding their own changes
+func mergePatch(before, after map[string]interface{}) Result {
+	patch := make(Result)
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			patch[key] = nil
+		}
+	}
+	for key, afterVal := range after {
+		beforeVal, ok := before[key]
+		beforeMap, beforeIsMap := beforeVal.(map[string]interface{})
+		afterMap, afterIsMap := afterVal.(map[string]interface{})
+		switch {
+		case ok && beforeIsMap && afterIsMap:
+			if changes := mergePatch(beforeMap, afterMap); len(changes) > 0 {
+				patch[key] = changes
+			}
+		case !ok || !reflect.DeepEqual(beforeVal, afterVal):
+			patch[key] = afterVal
+		}
+	}
+	return patch
+}
+
+// applyOp returns doc with op applied
+func applyOp(doc interface{}, op PatchOp) (interface{}, error) {
+	switch op.Op {
+	case OpAdd:
+		return addValue(doc, op.Path, copyResultValue(op.Value))
+	case OpRemove:
+		doc, _, err := removeValue(doc, op.Path)
+		return doc, err
+	case OpReplace:
+		if op.Path == "" {
+			return copyResultValue(op.Value), nil
+		}
+		doc, _, err := removeValue(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, op.Path, copyResultValue(op.Value))
+	case OpMove:
+		if op.Path != op.From && strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, fmt.Errorf("cannot move %s into itself", op.From)
+		}
+		doc, val, err := removeValue(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, op.Path, val)
+	case OpCopy:
+		val, err := pointerValue(doc, op.From)
+		if err != nil {
+			return nil, err
+		}
+		return addValue(doc, op.Path, copyResultValue(val))
+	case OpTest:
+		val, err := pointerValue(doc, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(val, op.Value) {
+			return nil, fmt.Errorf("test failed, the value is %v", val)
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// splitPointer returns the unescaped keys of a JSON Pointer ex: [a/b c] for /a~1b/c
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("pointer %q does not start with /", pointer)
+	}
+	keys := strings.Split(pointer[1:], "/")
+	for i, key := range keys {
+		keys[i] = strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+	}
+	return keys, nil
+}
+
+// escapePointer escapes a key for a JSON Pointer
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// pointerValue returns the value doc holds at pointer
+func pointerValue(doc interface{}, pointer string) (interface{}, error) {
+	keys, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if doc, err = childValue(doc, key); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// childValue returns the value of container at key, an object key or a list index
+func childValue(container interface{}, key string) (interface{}, error) {
+	switch c := container.(type) {
+	case map[string]interface{}:
+		val, ok := c[key]
+		if !ok {
+			return nil, fmt.Errorf("no key %q", key)
+		}
+		return val, nil
+	case []interface{}:
+		index, err := listIndex(key, len(c)-1)
+		if err != nil {
+			return nil, err
+		}
+		return c[index], nil
+	}
+	return nil, fmt.Errorf("%s holds no key %q", typeName(container), key)
+}
+
+// listIndex parses the list index key, which may not exceed max
+func listIndex(key string, max int) (int, error) {
+	index, err := strconv.Atoi(key)
+	if err != nil || index < 0 || index > max || key != strconv.Itoa(index) {
+		return 0, fmt.Errorf("no element %s in a list of %d", key, max+1)
+	}
+	return index, nil
+}
+
+// addValue returns doc with val added at pointer: set on objects, inserted into lists, - appending
+func addValue(doc interface{}, pointer string, val interface{}) (interface{}, error) {
+	keys, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return val, nil
+	}
+	return updateParent(doc, keys, func(parent interface{}, key string) (interface{}, error) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[key] = val
+			return p, nil
+		case []interface{}:
+			index := len(p)
+			if key != "-" {
+				if index, err = listIndex(key, len(p)); err != nil {
+					return nil, err
+				}
+			}
+			p = append(p, nil)
+			copy(p[index+1:], p[index:])
+			p[index] = val
+			return p, nil
+		}
+		return nil, fmt.Errorf("%s holds no key %q", typeName(parent), key)
+	})
+}
+
+// removeValue returns doc without the value at pointer, and that value
+func removeValue(doc interface{}, pointer string) (interface{}, interface{}, error) {
+	keys, err := splitPointer(pointer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(keys) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+	var removed interface{}
+	doc, err = updateParent(doc, keys, func(parent interface{}, key string) (interface{}, error) {
+		val, err := childValue(parent, key)
+		if err != nil {
+			return nil, err
+		}
+		removed = val
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			delete(p, key)
+			return p, nil
+		default:
+			index, _ := strconv.Atoi(key)
+			list := parent.([]interface{})
+			return append(list[:index:index], list[index+1:]...), nil
+		}
+	})
+	return doc, removed, err
+}
+
+// updateParent walks doc to the container of the last key and replaces it by the one update returns,
+// lists growing or shrinking are stored back into their own container
+func updateParent(doc interface{}, keys []string, update func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(keys) == 1 {
+		return update(doc, keys[0])
+	}
+	child, err := childValue(doc, keys[0])
+	if err != nil {
+		return nil, err
+	}
+	child, err = updateParent(child, keys[1:], update)
+	if err != nil {
+		return nil, err
+	}
+	switch d := doc.(type) {
+	case map[string]interface{}:
+		d[keys[0]] = child
+	case []interface{}:
+		index, _ := strconv.Atoi(keys[0])
+		d[index] = child
+	}
+	return doc, nil
+}
+
+// jsonEqual reports whether a and b are the same JSON value, numbers compared by value whatever
+// their Go type
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(jsonValue(a), jsonValue(b))
+}
+
+// jsonValue returns val as encoding/json decodes its JSON text, nil when it has none
+func jsonValue(val interface{}) interface{} {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
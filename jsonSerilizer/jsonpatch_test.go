@@ -0,0 +1,65 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type patchedDB struct {
+	Host string
+	Port int `default:"5432"`
+}
+
+type patchedConfig struct {
+	Name    string
+	Retries int `default:"3"`
+	DB      patchedDB
+	Replica *patchedDB
+	Labels  map[string]string
+	Tags    []string
+}
+
+func TestApplyPatchLeavesUntouchedFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		config patchedConfig
+	}{
+		{name: "zero value", config: patchedConfig{}},
+		{name: "set fields", config: patchedConfig{
+			Retries: 7,
+			DB:      patchedDB{Host: "db", Port: 9999},
+			Replica: &patchedDB{Host: "replica"},
+			Labels:  map[string]string{"env": "prod"},
+			Tags:    []string{"a", "b"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config
+			if err := ApplyPatch(&got, []PatchOp{{Op: OpReplace, Path: "/Name", Value: "renamed"}}); err != nil {
+				t.Fatalf("ApplyPatch() error = %v", err)
+			}
+			want := tt.config
+			want.Name = "renamed"
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if string(gotJSON) != string(wantJSON) {
+				t.Errorf("ApplyPatch() = %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestPatchSkipsDefaults(t *testing.T) {
+	config := patchedConfig{Retries: 7, DB: patchedDB{Port: 9999}}
+	changed, err := Patch(Result{"DB": map[string]interface{}{"Host": "h"}}, &config)
+	if err != nil {
+		t.Fatalf("Patch() error = %v", err)
+	}
+	if config.Retries != 7 || config.DB.Port != 9999 || config.DB.Host != "h" {
+		t.Errorf("Patch() = %+v, want Retries 7 and DB {h 9999}", config)
+	}
+	if len(changed) != 1 || changed[0] != "DB.Host" {
+		t.Errorf("Patch() changed = %v, want [DB.Host]", changed)
+	}
+}
@@ -0,0 +1,108 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Marshal returns the JSON text of a struct, or of a pointer to a struct, with the keys of Serialize
+func Marshal(v interface{}, opts ...Option) ([]byte, error) {
+	return MarshalWith(JSON, v, opts...)
+}
+
+// Unmarshal parses a JSON object and populates the struct out points to like Deserialize
+func Unmarshal(data []byte, out interface{}, opts ...Option) error {
+	return UnmarshalWith(JSON, data, out, opts...)
+}
+
+// structResult serializes a struct, or a pointer to a struct, for caller, pointer cycles are reported
+// as a *CycleError
+func structResult(caller string, v interface{}, opts []Option) (Result, error) {
+	return newOptions(opts).structResult(caller, v)
+}
+
+// structResult is the function structResult with the options of the call already applied
+func (o options) structResult(caller string, v interface{}) (Result, error) {
+	rVal := reflect.ValueOf(v)
+	for rVal.Kind() == reflect.Pointer {
+		if rVal.IsNil() {
+			return nil, fmt.Errorf("%s requires a non nil value", caller)
+		}
+		rVal = rVal.Elem()
+	}
+	if rVal.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s requires a struct, got %T", caller, v)
+	}
+	if rVal.CanAddr() {
+		// the struct v points to is part of the path, a field pointing back to it closes a cycle
+		o.cycles.enter(rVal.Addr())
+	}
+	r := o.serializeStruct(rVal)
+	if o.cycles.err != nil {
+		return nil, fmt.Errorf("%s: %w", caller, o.cycles.err)
+	}
+	return r, nil
+}
+
+// Encoder writes structs as JSON values to a stream, one per line
+type Encoder struct {
+	w    io.Writer
+	opts []Option
+}
+
+// NewEncoder returns an Encoder writing to w, opts apply to every Encode call
+func NewEncoder(w io.Writer, opts ...Option) *Encoder {
+	return &Encoder{w: w, opts: opts}
+}
+
+// SetIndent indents the JSON written by the next Encode calls like json.Encoder.SetIndent
+func (e *Encoder) SetIndent(prefix, indent string) {
+	e.opts = append(e.opts, WithIndent(prefix, indent))
+}
+
+// Encode writes the JSON of a struct, or of a pointer to a struct, followed by a newline
+func (e *Encoder) Encode(v interface{}) error {
+	o := newOptions(e.opts)
+	r, err := o.structResult("Encode", v)
+	if err != nil {
+		return err
+	}
+	data, err := o.encodeJSON(r)
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(append(data, '\n'))
+	return err
+}
+
+// Decoder reads JSON objects from a stream, such as a file, an HTTP body or newline delimited JSON
+type Decoder struct {
+	dec  *json.Decoder
+	opts []Option
+}
+
+// NewDecoder returns a Decoder reading from r, opts apply to every Decode call
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	dec := json.NewDecoder(r)
+	if newOptions(opts).useNumber {
+		dec.UseNumber()
+	}
+	return &Decoder{dec: dec, opts: opts}
+}
+
+// More reports whether there is another value to decode
+func (d *Decoder) More() bool {
+	return d.dec.More()
+}
+
+// Decode reads the next JSON object and populates the struct out points to like Deserialize.
+// It returns io.EOF once the stream is exhausted.
+func (d *Decoder) Decode(out interface{}) error {
+	var r Result
+	if err := d.dec.Decode(&r); err != nil {
+		return err
+	}
+	return Deserialize(r, out, d.opts...)
+}
@@ -0,0 +1,95 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ResultMarshaler is implemented by types choosing their own value in a Result ex: a Money type
+// stored as "12.50 EUR", or an enum stored by name
+type ResultMarshaler interface {
+	// MarshalResult returns the value stored for the receiver, a string, number, bool, []interface{}
+	// or map[string]interface{} so the Result can be written as JSON
+	MarshalResult() interface{}
+}
+
+// ResultUnmarshaler is implemented by types reading themselves from a Result value, the counterpart
+// of ResultMarshaler
+type ResultUnmarshaler interface {
+	// UnmarshalResult sets the receiver from the value stored in the Result, as decoded from JSON
+	UnmarshalResult(val interface{}) error
+}
+
+var (
+	resultMarshalerType   = reflect.TypeOf((*ResultMarshaler)(nil)).Elem()
+	resultUnmarshalerType = reflect.TypeOf((*ResultUnmarshaler)(nil)).Elem()
+	marshalerType         = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType       = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// implementer returns val, or its address, as the iface it implements
+func implementer(val reflect.Value, iface reflect.Type) (interface{}, bool) {
+	if val.Type().Implements(iface) {
+		return val.Interface(), true
+	}
+	if val.CanAddr() && val.Addr().Type().Implements(iface) {
+		return val.Addr().Interface(), true
+	}
+	return nil, false
+}
+
+// marshalResult returns the value a ResultMarshaler chose for val
+func marshalResult(val reflect.Value) (interface{}, bool) {
+	m, ok := implementer(val, resultMarshalerType)
+	if !ok {
+		return nil, false
+	}
+	return m.(ResultMarshaler).MarshalResult(), true
+}
+
+// marshalJSON returns the decoded output of MarshalJSON for a json.Marshaler, so the Result holds
+// plain values. val is kept as it is when MarshalJSON fails, so that Marshal reports the error.
+func marshalJSON(val reflect.Value) (interface{}, bool) {
+	m, ok := implementer(val, marshalerType)
+	if !ok {
+		return nil, false
+	}
+	data, err := m.(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return val.Interface(), true
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return val.Interface(), true
+	}
+	return out, true
+}
+
+// unmarshalResult lets a field implementing ResultUnmarshaler read val, handled is false otherwise
+func (d *decoder) unmarshalResult(field reflect.Value, val interface{}, path string) (handled bool) {
+	u, ok := implementer(field, resultUnmarshalerType)
+	if !ok {
+		return false
+	}
+	if err := u.(ResultUnmarshaler).UnmarshalResult(val); err != nil {
+		d.fail(path, field, val, err.Error())
+	}
+	return true
+}
+
+// unmarshalJSON lets a field implementing json.Unmarshaler read val encoded back to JSON,
+// handled is false otherwise
+func (d *decoder) unmarshalJSON(field reflect.Value, val interface{}, path string) (handled bool) {
+	u, ok := implementer(field, unmarshalerType)
+	if !ok {
+		return false
+	}
+	data, err := json.Marshal(val)
+	if err == nil {
+		err = u.(json.Unmarshaler).UnmarshalJSON(data)
+	}
+	if err != nil {
+		d.fail(path, field, val, err.Error())
+	}
+	return true
+}
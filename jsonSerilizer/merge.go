@@ -0,0 +1,66 @@
+package jsonserilizer
+
+// MergeStrategy chooses how MergeResults combines values found in both Results, strategies are
+// combined with | ex: MergeDeepMaps|MergeAppendSlices
+type MergeStrategy int
+
+const (
+	// MergeOverride replaces the values of base with those of override, objects and lists included
+	MergeOverride MergeStrategy = 0
+	// MergeDeepMaps merges objects key by key, recursively, instead of replacing them
+	MergeDeepMaps MergeStrategy = 1 << (iota - 1)
+	// MergeAppendSlices appends the elements of override lists to those of base
+	MergeAppendSlices
+)
+
+// MergeResults returns base with override applied on top, for layered configuration ex: defaults,
+// then a file, then the environment, composed before a single Deserialize. Keys of override win,
+// a null included, and strategy chooses how objects and lists found on both sides combine. Neither
+// Result is modified, the merged one shares no map or list with them.
+func MergeResults(base, override Result, strategy MergeStrategy) Result {
+	merged := copyResultValue(map[string]interface{}(base)).(map[string]interface{})
+	mergeMaps(merged, override, strategy)
+	return merged
+}
+
+// mergeMaps applies the entries of override to dst
+func mergeMaps(dst, override map[string]interface{}, strategy MergeStrategy) {
+	for key, val := range override {
+		current, exists := dst[key]
+		if !exists {
+			dst[key] = copyResultValue(val)
+			continue
+		}
+		currentMap, currentIsMap := asMap(current)
+		overrideMap, overrideIsMap := asMap(val)
+		currentList, currentIsList := current.([]interface{})
+		overrideList, overrideIsList := val.([]interface{})
+		switch {
+		case strategy&MergeDeepMaps != 0 && currentIsMap && overrideIsMap:
+			mergeMaps(currentMap, overrideMap, strategy)
+		case strategy&MergeAppendSlices != 0 && currentIsList && overrideIsList:
+			dst[key] = append(currentList, copyResultValue(overrideList).([]interface{})...)
+		default:
+			dst[key] = copyResultValue(val)
+		}
+	}
+}
+
+// copyResultValue deep copies the objects and lists of a Result value, Results become plain maps
+func copyResultValue(val interface{}) interface{} {
+	if m, ok := asMap(val); ok {
+		out := make(map[string]interface{}, len(m))
+		for key, elem := range m {
+			out[key] = copyResultValue(elem)
+		}
+		return out
+	}
+	if elems, ok := val.([]interface{}); ok {
+		out := make([]interface{}, len(elems))
+		for i, elem := range elems {
+			out[i] = copyResultValue(elem)
+		}
+		return out
+	}
+	return val
+}
@@ -0,0 +1,34 @@
+package jsonserilizer
+
+import "sort"
+
+// Metadata reports how Deserialize used a Result, for partial updates and diagnostics, see
+// WithMetadata. Paths are sorted.
+type Metadata struct {
+	// Keys are the paths of the keys stored into a field ex: Home and Home.City
+	Keys []string
+	// Unused are the paths of the keys no field matched, those WithStrict reports
+	Unused []string
+	// Unset are the paths of the fields left untouched because their key was missing ex: Home.Zip,
+	// fields given their default are set. A missing nested struct lists its fields.
+	Unset []string
+}
+
+// WithMetadata fills md with the keys Deserialize used and ignored and the fields it left unset,
+// replacing what md held
+func WithMetadata(md *Metadata) Option {
+	return func(o *options) {
+		o.metadata = md
+	}
+}
+
+// fillMetadata stores the paths recorded by the call into its Metadata, if any
+func (d *decoder) fillMetadata() {
+	if d.metadata == nil {
+		return
+	}
+	for _, paths := range [][]string{d.usedKeys, d.unusedKeys, d.unset} {
+		sort.Strings(paths)
+	}
+	*d.metadata = Metadata{Keys: d.usedKeys, Unused: d.unusedKeys, Unset: d.unset}
+}
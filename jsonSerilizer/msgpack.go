@@ -0,0 +1,361 @@
+package jsonserilizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// MsgPack reads and writes MessagePack, a compact binary format for services exchanging many
+// documents. []byte is written as binary rather than base64, and the timestamp extension is read
+// into time.Time values and written from them.
+var MsgPack Codec = msgpackCodec{}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Encode(r Result) ([]byte, error) {
+	return appendMsgpack(nil, map[string]interface{}(r))
+}
+
+func (msgpackCodec) Decode(data []byte) (Result, error) {
+	dec := msgpackDecoder{data: data}
+	val, err := dec.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if dec.pos != len(data) {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(data)-dec.pos)
+	}
+	r, ok := val.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("msgpack: expected a map, got %s", typeName(val))
+	}
+	return r, nil
+}
+
+// msgpackTimestamp is the extension type of timestamps
+const msgpackTimestamp = -1
+
+// msgpackMaxDepth bounds the nesting of decoded arrays and maps
+const msgpackMaxDepth = 10000
+
+// appendMsgpack appends the encoding of val to out, integers in their shortest form
+func appendMsgpack(out []byte, val interface{}) ([]byte, error) {
+	switch v := val.(type) {
+	case nil:
+		return append(out, 0xc0), nil
+	case bool:
+		if v {
+			return append(out, 0xc3), nil
+		}
+		return append(out, 0xc2), nil
+	case string:
+		return appendMsgpackString(out, v), nil
+	case []byte:
+		switch n := len(v); {
+		case n <= math.MaxUint8:
+			out = append(out, 0xc4, byte(n))
+		case n <= math.MaxUint16:
+			out = binary.BigEndian.AppendUint16(append(out, 0xc5), uint16(n))
+		default:
+			out = binary.BigEndian.AppendUint32(append(out, 0xc6), uint32(n))
+		}
+		return append(out, v...), nil
+	case time.Time:
+		return appendMsgpackTimestamp(out, v), nil
+	case Result:
+		return appendMsgpack(out, map[string]interface{}(v))
+	case map[string]interface{}:
+		out = appendMsgpackHeader(out, len(v), 0x80, 0xde)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var err error
+		for _, key := range keys {
+			out = appendMsgpackString(out, key)
+			if out, err = appendMsgpack(out, v[key]); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []interface{}:
+		out = appendMsgpackHeader(out, len(v), 0x90, 0xdc)
+		var err error
+		for _, elem := range v {
+			if out, err = appendMsgpack(out, elem); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	// named types ex: time.Duration or an enum
+	rVal := reflect.ValueOf(val)
+	switch rVal.Kind() {
+	case reflect.String:
+		return appendMsgpackString(out, rVal.String()), nil
+	case reflect.Bool:
+		return appendMsgpack(out, rVal.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendMsgpackInt(out, rVal.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := rVal.Uint()
+		if n <= math.MaxInt64 {
+			return appendMsgpackInt(out, int64(n)), nil
+		}
+		return binary.BigEndian.AppendUint64(append(out, 0xcf), n), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(append(out, 0xca), math.Float32bits(float32(rVal.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(append(out, 0xcb), math.Float64bits(rVal.Float())), nil
+	}
+	return nil, fmt.Errorf("msgpack: cannot encode %T", val)
+}
+
+func appendMsgpackInt(out []byte, n int64) []byte {
+	switch {
+	case n >= 0 && n <= 0x7f, n < 0 && n >= -32:
+		return append(out, byte(n))
+	case n >= 0 && n <= math.MaxUint8:
+		return append(out, 0xcc, byte(n))
+	case n >= 0 && n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, 0xcd), uint16(n))
+	case n >= 0 && n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(out, 0xce), uint32(n))
+	case n >= math.MinInt8 && n < 0:
+		return append(out, 0xd0, byte(n))
+	case n >= math.MinInt16 && n < 0:
+		return binary.BigEndian.AppendUint16(append(out, 0xd1), uint16(n))
+	case n >= math.MinInt32 && n < 0:
+		return binary.BigEndian.AppendUint32(append(out, 0xd2), uint32(n))
+	}
+	return binary.BigEndian.AppendUint64(append(out, 0xd3), uint64(n))
+}
+
+// appendMsgpackTimestamp appends t as the timestamp extension, in the shortest of the 32, 64 and 96
+// bit forms ext reads
+func appendMsgpackTimestamp(out []byte, t time.Time) []byte {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	switch {
+	case nsec == 0 && sec >= 0 && sec <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(out, 0xd6, 0xff), uint32(sec))
+	case sec >= 0 && sec < 1<<34:
+		return binary.BigEndian.AppendUint64(append(out, 0xd7, 0xff), nsec<<34|uint64(sec))
+	}
+	out = binary.BigEndian.AppendUint32(append(out, 0xc7, 12, 0xff), uint32(nsec))
+	return binary.BigEndian.AppendUint64(out, uint64(sec))
+}
+
+func appendMsgpackString(out []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		out = append(out, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		out = append(out, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		out = binary.BigEndian.AppendUint16(append(out, 0xda), uint16(n))
+	default:
+		out = binary.BigEndian.AppendUint32(append(out, 0xdb), uint32(n))
+	}
+	return append(out, s...)
+}
+
+// appendMsgpackHeader appends the header of an array or a map of n entries, fix is the prefix of
+// the short form and long the first of the 16 and 32 bit forms
+func appendMsgpackHeader(out []byte, n int, fix, long byte) []byte {
+	switch {
+	case n < 16:
+		return append(out, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(out, long), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(out, long+1), uint32(n))
+}
+
+// msgpackDecoder reads MessagePack values, integers as int64 or uint64 and floats as float64
+type msgpackDecoder struct {
+	data []byte
+	pos  int
+}
+
+var errMsgpackShort = errors.New("msgpack: unexpected end of data")
+
+// next returns the next n bytes
+func (d *msgpackDecoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errMsgpackShort
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// uint reads a big endian unsigned integer of size bytes
+func (d *msgpackDecoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, c := range b {
+		n = n<<8 | uint64(c)
+	}
+	return n, nil
+}
+
+func (d *msgpackDecoder) value(depth int) (interface{}, error) {
+	if depth > msgpackMaxDepth {
+		return nil, errors.New("msgpack: nesting too deep")
+	}
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	case c&0xf0 == 0x90:
+		return d.array(int(c&0x0f), depth)
+	case c&0xf0 == 0x80:
+		return d.dict(int(c&0x0f), depth)
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := d.uint(1 << (c - 0xcc))
+		if err != nil || n > math.MaxInt64 {
+			return n, err
+		}
+		return int64(n), nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := d.uint(size)
+		// sign extend from size bytes
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, err
+	case 0xca:
+		n, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := d.uint(8)
+		return math.Float64frombits(n), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		return d.str(int(n))
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(int(n))
+		return append([]byte(nil), b...), err
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(int(n), depth)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.dict(int(n), depth)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		return d.ext(int(n))
+	}
+	return nil, fmt.Errorf("msgpack: invalid type byte 0x%02x", c)
+}
+
+func (d *msgpackDecoder) str(n int) (string, error) {
+	b, err := d.next(n)
+	return string(b), err
+}
+
+func (d *msgpackDecoder) array(n int, depth int) ([]interface{}, error) {
+	// every element takes at least a byte, refuse lengths the data cannot hold before allocating
+	if n > len(d.data)-d.pos {
+		return nil, errMsgpackShort
+	}
+	elems := make([]interface{}, n)
+	for i := range elems {
+		elem, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		elems[i] = elem
+	}
+	return elems, nil
+}
+
+// dict reads a map of n entries, keys other than strings are stored by their text ex: "1"
+func (d *msgpackDecoder) dict(n int, depth int) (map[string]interface{}, error) {
+	if n > (len(d.data)-d.pos)/2 {
+		return nil, errMsgpackShort
+	}
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		val, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(key)] = val
+	}
+	return m, nil
+}
+
+// ext reads an extension of n bytes of data, only timestamps are supported
+func (d *msgpackDecoder) ext(n int) (interface{}, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+	typ := int8(b[0])
+	data, err := d.next(n)
+	if err != nil {
+		return nil, err
+	}
+	if typ != msgpackTimestamp {
+		return nil, fmt.Errorf("msgpack: unsupported extension type %d", typ)
+	}
+	switch n {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC(), nil
+	case 8:
+		n := binary.BigEndian.Uint64(data)
+		return time.Unix(int64(n&(1<<34-1)), int64(n>>34)).UTC(), nil
+	case 12:
+		return time.Unix(int64(binary.BigEndian.Uint64(data[4:])), int64(binary.BigEndian.Uint32(data))).UTC(), nil
+	}
+	return nil, fmt.Errorf("msgpack: invalid timestamp of %d bytes", n)
+}
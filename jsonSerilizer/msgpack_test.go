@@ -0,0 +1,41 @@
+package jsonserilizer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMsgPackTimestampRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		size int
+	}{
+		{name: "32 bit", time: time.Unix(1700000000, 0).UTC(), size: 6},
+		{name: "64 bit", time: time.Unix(1700000000, 500).UTC(), size: 10},
+		{name: "96 bit", time: time.Unix(-1, 999).UTC(), size: 15},
+		{name: "96 bit after 2514", time: time.Unix(1<<34, 0).UTC(), size: 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MsgPack.Encode(Result{"At": tt.time})
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			// the map header and the key take 4 bytes
+			if got := len(data) - 4; got != tt.size {
+				t.Errorf("Encode() wrote a %d byte timestamp, want %d", got, tt.size)
+			}
+			decoded, err := MsgPack.Decode(data)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got, _ := decoded["At"].(time.Time); !got.Equal(tt.time) {
+				t.Errorf("Decode() = %v, want %v", decoded["At"], tt.time)
+			}
+			if _, err := MsgPack.Encode(decoded); err != nil {
+				t.Errorf("Encode() of the decoded document error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package jsonserilizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamingStrategy turns a Go field name into a key ex: SnakeCase, fields tagged with a json name keep it
+type NamingStrategy func(fieldName string) string
+
+// SnakeCase renders HomeAddress as home_address
+func SnakeCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "_"))
+}
+
+// KebabCase renders HomeAddress as home-address
+func KebabCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "-"))
+}
+
+// CamelCase renders HomeAddress as homeAddress
+func CamelCase(fieldName string) string {
+	words := splitWords(fieldName)
+	for i, word := range words {
+		word = strings.ToLower(word)
+		if i > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		words[i] = word
+	}
+	return strings.Join(words, "")
+}
+
+// WithNaming derives the keys of untagged fields from their Go name with naming,
+// use the same strategy to Serialize and Deserialize a document
+func WithNaming(naming NamingStrategy) Option {
+	return func(o *options) {
+		o.naming = naming
+	}
+}
+
+// SerializeWithNaming is Serialize with keys derived by naming ex: SerializeWithNaming(s, SnakeCase)
+func SerializeWithNaming(s interface{}, naming NamingStrategy, opts ...Option) Result {
+	return Serialize(s, append(opts, WithNaming(naming))...)
+}
+
+// splitWords splits a Go identifier into words, keeping acronyms together ex: UserID -> User ID, HTTPServer -> HTTP Server
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		lowerToUpper := unicode.IsUpper(cur) && !unicode.IsUpper(prev)
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if cur == '_' || lowerToUpper || acronymEnd {
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+			if cur == '_' {
+				start = i + 1
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,116 @@
+package jsonserilizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// WithUseNumber decodes JSON numbers as json.Number like json.Decoder.UseNumber, so integers beyond
+// 2^53 such as int64 IDs keep their precision: interface{} fields and map values hold the
+// json.Number, and numeric fields parse its text, numbers that do not fit the field are reported
+// instead of truncated. It applies to Unmarshal, UnmarshalWith(JSON, ...), Decoder and BindRequest.
+func WithUseNumber() Option {
+	return func(o *options) {
+		o.useNumber = true
+	}
+}
+
+// decodeNumbers reads a JSON object with the numbers as json.Number
+func (jsonCodec) decodeNumbers(data []byte) (Result, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var r Result
+	if err := dec.Decode(&r); err != nil {
+		return nil, err
+	}
+	// like json.Unmarshal, only whitespace may follow the object
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level value")
+	}
+	return r, nil
+}
+
+// decode reads data with codec, JSON numbers as json.Number WithUseNumber
+func decode(codec Codec, data []byte, opts []Option) (Result, error) {
+	if c, ok := codec.(jsonCodec); ok && newOptions(opts).useNumber {
+		return c.decodeNumbers(data)
+	}
+	return codec.Decode(data)
+}
+
+// setNumber stores a json.Number into a numeric field, parsing its text so an int64 keeps all of its
+// digits. Integers written with a fraction or an exponent ex: 1e3 are read through float64.
+func (d *decoder) setNumber(field reflect.Value, num json.Number, path string) {
+	text := num.String()
+	var err error
+	switch {
+	case field.CanInt():
+		var n int64
+		if n, err = strconv.ParseInt(text, 10, field.Type().Bits()); errors.Is(err, strconv.ErrSyntax) {
+			n, err = floatInteger(text, float64(math.MinInt64), float64(math.MaxInt64))
+			if err == nil && field.OverflowInt(n) {
+				err = strconv.ErrRange
+			}
+		}
+		if err == nil {
+			field.SetInt(n)
+		}
+	case field.CanUint():
+		var n uint64
+		if n, err = strconv.ParseUint(text, 10, field.Type().Bits()); errors.Is(err, strconv.ErrSyntax) {
+			var i int64
+			if i, err = floatInteger(text, 0, float64(math.MaxInt64)); err == nil {
+				n = uint64(i)
+				if field.OverflowUint(n) {
+					err = strconv.ErrRange
+				}
+			}
+		}
+		if err == nil {
+			field.SetUint(n)
+		}
+	default:
+		var f float64
+		if f, err = strconv.ParseFloat(text, field.Type().Bits()); err == nil {
+			field.SetFloat(f)
+		}
+	}
+	if err != nil {
+		d.fail(path, field, num, fmt.Sprintf("%s does not fit", text))
+	}
+}
+
+// numberFits reports whether the number val converts to the type of field and back unchanged.
+// The signs are checked first, a conversion wrapping -1 to the largest uint converts back to -1.
+func numberFits(val, field reflect.Value) bool {
+	switch {
+	case val.CanInt() && field.CanUint() && val.Int() < 0:
+		return false
+	case val.CanUint() && field.CanInt() && val.Uint() > math.MaxInt64:
+		return false
+	}
+	return val.Convert(field.Type()).Convert(val.Type()).Equal(val)
+}
+
+// floatInteger parses text as a float64 holding an integer between min and max
+func floatInteger(text string, min, max float64) (int64, error) {
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != math.Trunc(f) || f < min || f >= max {
+		return 0, strconv.ErrRange
+	}
+	return int64(f), nil
+}
+
+// numberValue returns the value of a json.Number as a number of typ, ok is false when it does not fit
+func numberValue(num json.Number, typ reflect.Type) (reflect.Value, bool) {
+	out := reflect.New(typ).Elem()
+	d := &decoder{}
+	d.setNumber(out, num, "")
+	return out, len(d.errs) == 0
+}
@@ -0,0 +1,52 @@
+package jsonserilizer
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+type unsignedCount struct {
+	N uint
+}
+
+type signedCount struct {
+	N int64
+}
+
+func TestDeserializeRejectsSignWraps(t *testing.T) {
+	// {"N": -1} as MsgPack, decoded as an int64
+	negative, err := MsgPack.Decode([]byte{0x81, 0xa1, 'N', 0xff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		doc    Result
+		target interface{}
+	}{
+		{name: "signed into unsigned", doc: negative, target: &unsignedCount{}},
+		{name: "int into unsigned", doc: Result{"N": -1}, target: &unsignedCount{}},
+		{name: "uint64 above MaxInt64 into int64", doc: Result{"N": uint64(math.MaxUint64)}, target: &signedCount{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Deserialize(tt.doc, tt.target)
+			var errs FieldTypeErrors
+			if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "N" {
+				t.Errorf("Deserialize() error = %v, want a FieldTypeError for N", err)
+			}
+		})
+	}
+}
+
+func TestDeserializeKeepsFittingNumbers(t *testing.T) {
+	var unsigned unsignedCount
+	if err := Deserialize(Result{"N": int64(7)}, &unsigned); err != nil || unsigned.N != 7 {
+		t.Errorf("Deserialize() = %d, %v, want 7", unsigned.N, err)
+	}
+	var signed signedCount
+	if err := Deserialize(Result{"N": uint64(math.MaxInt64)}, &signed); err != nil || signed.N != math.MaxInt64 {
+		t.Errorf("Deserialize() = %d, %v, want MaxInt64", signed.N, err)
+	}
+}
@@ -0,0 +1,118 @@
+package jsonserilizer
+
+// Option adjusts a single Serialize, Deserialize, Marshal or Unmarshal call
+type Option func(*options)
+
+// options holds the settings of a call
+type options struct {
+	omitEmpty bool
+	naming    NamingStrategy
+	// stringCoercion converts strings to and from numbers and booleans, see WithStringCoercion
+	stringCoercion bool
+	// weak interchanges strings, numbers and booleans, see WithWeaklyTypedInput
+	weak bool
+	// caseInsensitive matches keys to fields regardless of case, see WithCaseInsensitiveKeys
+	caseInsensitive bool
+	// strict reports keys without a field, see WithStrict
+	strict bool
+	// requiredKeys reports missing keys tagged required, see WithRequiredKeys
+	requiredKeys bool
+	// timeLayout is the time_format of the field being converted, see layout
+	timeLayout string
+	// useNumber decodes JSON numbers as json.Number, see WithUseNumber
+	useNumber bool
+	// xml reads the xml tags and the element conventions of the XML codec
+	xml bool
+	// bson reads the bson tags and keeps time.Time values, see WithBSON
+	bson bool
+	// csv reads lists and maps from the JSON text of CSV cells, see UnmarshalCSV
+	csv bool
+	// values reads the conventions of query strings and HTML forms, see DeserializeValues
+	values bool
+	// flat reads lists back from the indexes of SerializeFlat, see DeserializeFlat
+	flat bool
+	// patch merges objects into the existing pointers and maps, see Patch
+	patch bool
+	// zeroNulls clears the fields of every kind set to null, see ApplyPatch
+	zeroNulls bool
+	// metadata receives the report of Deserialize, see WithMetadata
+	metadata *Metadata
+	// keyOrder, indentPrefix and indent shape the JSON of Marshal, see WithKeyOrder and WithIndent
+	keyOrder             KeyOrder
+	indentPrefix, indent string
+	// jsonArray writes a JSON array from EncodeStream, see WithJSONArray
+	jsonArray bool
+	// orders holds the field order of the structs serialized WithKeyOrder(DeclarationOrder)
+	orders map[uintptr]orderedKeys
+	// groups limit the call to the fields of some views, see WithGroups
+	groups []string
+	// hooks rewrite the values Deserialize stores, see WithDecodeHook
+	hooks []DecodeHookFunc
+	// cycles tracks the pointers being serialized by the call, see CycleError
+	cycles *cycleState
+	// maxBodySize bounds the request bodies read by BindRequest, see WithMaxBodySize
+	maxBodySize int64
+}
+
+// WithCaseInsensitiveKeys lets Deserialize match keys to fields regardless of case like encoding/json
+// ex: name populates Name, an exact match wins over the others
+func WithCaseInsensitiveKeys() Option {
+	return func(o *options) {
+		o.caseInsensitive = true
+	}
+}
+
+// WithOmitEmpty leaves every zero value field out of the output, as if all fields were tagged
+// omitempty, to produce sparse documents such as PATCH payloads
+func WithOmitEmpty() Option {
+	return func(o *options) {
+		o.omitEmpty = true
+	}
+}
+
+// fieldInfo returns what the tags of field say, along with the Options registered for its types,
+// naming the keys of untagged fields with the naming strategy
+func (o options) fieldInfo(field cachedField) fieldInfo {
+	info, naming := typeFieldInfo(field, o.naming)
+	if !info.tagged && naming != nil {
+		info.key = naming(field.Name)
+	}
+	if o.xml {
+		info = xmlFieldInfo(field.StructField, info)
+	}
+	if o.bson {
+		info = bsonFieldInfo(field.StructField, info)
+	}
+	if !o.inGroups(info.groups) {
+		return fieldInfo{skip: true}
+	}
+	return info
+}
+
+// WithStrict makes Deserialize report the keys no field matches, nested ones included, to catch
+// typos such as emial. The other fields are still populated.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithRequiredKeys makes Deserialize report the keys tagged required ex: `json:"port,required"` that
+// are missing, as a *MissingKeysError. Defaults do not satisfy it, a required key must be given.
+func WithRequiredKeys() Option {
+	return func(o *options) {
+		o.requiredKeys = true
+	}
+}
+
+// newOptions applies opts in order
+func newOptions(opts []Option) options {
+	o := options{cycles: &cycleState{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.keyOrder == DeclarationOrder {
+		o.orders = make(map[uintptr]orderedKeys)
+	}
+	return o
+}
@@ -0,0 +1,141 @@
+package jsonserilizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// KeyOrder is the order Marshal writes the keys of JSON objects in, see WithKeyOrder
+type KeyOrder int
+
+const (
+	// AlphabeticalOrder sorts the keys, the default like encoding/json
+	AlphabeticalOrder KeyOrder = iota
+	// DeclarationOrder writes the keys of structs in field order, squashed fields in place. The keys
+	// of maps, and those a remain field adds, follow in alphabetical order.
+	DeclarationOrder
+)
+
+// WithKeyOrder sets the order of the keys written by Marshal, MarshalWith(JSON, ...) and Encoder.
+// Either order writes the same value as the same text, so output stays diff friendly.
+func WithKeyOrder(order KeyOrder) Option {
+	return func(o *options) {
+		o.keyOrder = order
+	}
+}
+
+// WithIndent indents the JSON written by Marshal, MarshalWith(JSON, ...) and Encoder like
+// json.MarshalIndent, each element on a new line starting with prefix and indented by indent
+func WithIndent(prefix, indent string) Option {
+	return func(o *options) {
+		o.indentPrefix, o.indent = prefix, indent
+	}
+}
+
+// orderedKeys holds the field order of a serialized struct, and the Result itself so its address,
+// the key of options.orders, is not reused while the order is known
+type orderedKeys struct {
+	result Result
+	keys   []string
+}
+
+// fieldOrder appends the keys of result set by the fields of typ to order, squashed fields in place
+func (o options) fieldOrder(typ reflect.Type, result Result, order []string) []string {
+	for _, field := range structFields(typ) {
+		info := o.fieldInfo(field)
+		switch {
+		case info.skip:
+		case info.squash:
+			order = o.fieldOrder(structType(field.Type), result, order)
+		default:
+			if _, ok := result[info.key]; ok {
+				order = append(order, info.key)
+			}
+		}
+	}
+	return order
+}
+
+// encodeJSON writes r as JSON with the key order and the indentation of the call
+func (o options) encodeJSON(r Result) ([]byte, error) {
+	if o.orders == nil && o.indentPrefix == "" && o.indent == "" {
+		return json.Marshal(r)
+	}
+	var out bytes.Buffer
+	if err := o.writeJSON(&out, r); err != nil {
+		return nil, err
+	}
+	if o.indentPrefix == "" && o.indent == "" {
+		return out.Bytes(), nil
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, out.Bytes(), o.indentPrefix, o.indent); err != nil {
+		return nil, err
+	}
+	return indented.Bytes(), nil
+}
+
+// writeJSON writes val as compact JSON, the keys of structs in declaration order when recorded
+func (o options) writeJSON(out *bytes.Buffer, val interface{}) error {
+	switch v := val.(type) {
+	case Result:
+		return o.writeJSON(out, map[string]interface{}(v))
+	case map[string]interface{}:
+		out.WriteByte('{')
+		for i, key := range o.jsonKeys(v) {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			keyData, _ := json.Marshal(key)
+			out.Write(keyData)
+			out.WriteByte(':')
+			if err := o.writeJSON(out, v[key]); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+		return nil
+	case []interface{}:
+		out.WriteByte('[')
+		for i, elem := range v {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := o.writeJSON(out, elem); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		return nil
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	out.Write(data)
+	return nil
+}
+
+// jsonKeys returns the keys of m, those of a struct in field order and the others sorted
+func (o options) jsonKeys(m map[string]interface{}) []string {
+	var keys []string
+	seen := make(map[string]bool, len(m))
+	if ordered, ok := o.orders[reflect.ValueOf(m).Pointer()]; ok {
+		for _, key := range ordered.keys {
+			if _, ok := m[key]; ok && !seen[key] {
+				keys = append(keys, key)
+				seen[key] = true
+			}
+		}
+	}
+	rest := make([]string, 0, len(m)-len(keys))
+	for key := range m {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
@@ -0,0 +1,113 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Get returns the value at path ex: Address.City, Tags.0 or Tags[0], nil when it is missing. Keys
+// are separated by dots, and list indexes or map keys holding a dot can also be written in
+// brackets ex: Scores[a.b], as in the paths of FieldTypeError.
+func (r Result) Get(path string) interface{} {
+	val, _ := r.lookupPath(path)
+	return val
+}
+
+// Has reports whether r holds a value at path, null values included
+func (r Result) Has(path string) bool {
+	_, ok := r.lookupPath(path)
+	return ok
+}
+
+// Set stores val at path, creating the maps of missing keys. List elements are replaced in place,
+// an index past the end of a list is an error.
+func (r Result) Set(path string, val interface{}) error {
+	keys, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+	var parent interface{} = map[string]interface{}(r)
+	for i, key := range keys {
+		last := i == len(keys)-1
+		switch p := parent.(type) {
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(p) {
+				return fmt.Errorf("Set %s: no element %s in a list of %d", path, key, len(p))
+			}
+			if last {
+				p[index] = val
+				return nil
+			}
+			parent = p[index]
+		default:
+			m, ok := asMap(p)
+			if !ok {
+				return fmt.Errorf("Set %s: %s holds %s", path, strings.Join(keys[:i], "."), typeName(p))
+			}
+			if last {
+				m[key] = val
+				return nil
+			}
+			if m[key] == nil {
+				m[key] = map[string]interface{}{}
+			}
+			parent = m[key]
+		}
+	}
+	return nil
+}
+
+// lookupPath returns the value at path, ok is false when a key is missing
+func (r Result) lookupPath(path string) (val interface{}, ok bool) {
+	keys, err := splitPath(path)
+	if err != nil {
+		return nil, false
+	}
+	val = map[string]interface{}(r)
+	for _, key := range keys {
+		if elems, isList := val.([]interface{}); isList {
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(elems) {
+				return nil, false
+			}
+			val = elems[index]
+			continue
+		}
+		m, isMap := asMap(val)
+		if !isMap {
+			return nil, false
+		}
+		if val, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// splitPath returns the keys of a dotted path ex: [Tags 0 Scores a.b] for Tags.0.Scores[a.b]
+func splitPath(path string) ([]string, error) {
+	var keys []string
+	for rest := path; rest != ""; {
+		if inner, ok := strings.CutPrefix(rest, "["); ok {
+			key, after, found := strings.Cut(inner, "]")
+			if !found {
+				return nil, fmt.Errorf("path %s: missing ]", path)
+			}
+			keys = append(keys, key)
+			rest = strings.TrimPrefix(after, ".")
+			continue
+		}
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		keys = append(keys, rest[:end])
+		rest = strings.TrimPrefix(rest[end:], ".")
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("empty path")
+	}
+	return keys, nil
+}
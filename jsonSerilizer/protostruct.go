@@ -0,0 +1,129 @@
+package jsonserilizer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// StructValues converts r into the values google.protobuf.Struct holds, so validated data can flow
+// into gRPC APIs through structpb without a conversion layer and without this package depending on
+// protobuf:
+//
+//	s, err := jsonserilizer.StructValues(r)
+//	if err != nil {
+//		return err
+//	}
+//	pb, err := structpb.NewStruct(s)
+//
+// Numbers become float64, the number_value of a Value, time.Time values their RFC3339 text, []byte
+// its base64 text, and named types ex: time.Duration or an enum their underlying value. Values a
+// Value cannot hold such as channels are reported with their key path. The way back needs no
+// helper: Result(pb.AsMap()) reads like any decoded JSON document, numbers included.
+func StructValues(r Result) (map[string]interface{}, error) {
+	val, err := structValue(map[string]interface{}(r), "")
+	if err != nil {
+		return nil, err
+	}
+	return val.(map[string]interface{}), nil
+}
+
+// StructValue converts a single value of a Result like StructValues, for structpb.NewValue
+func StructValue(val interface{}) (interface{}, error) {
+	return structValue(val, "")
+}
+
+// structValue converts val found at path into the values of google.protobuf.Value
+func structValue(val interface{}, path string) (interface{}, error) {
+	switch v := val.(type) {
+	case nil, bool, string, float64:
+		return v, nil
+	case Result:
+		return structValue(map[string]interface{}(v), path)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			converted, err := structValue(elem, fieldPath(path, key))
+			if err != nil {
+				return nil, err
+			}
+			out[key] = converted
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			converted, err := structValue(elem, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			out[i] = converted
+		}
+		return out, nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case time.Time:
+		return v.Format(defaultTimeLayout), nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, structError(path, err.Error())
+		}
+		return f, nil
+	}
+
+	// named types ex: time.Duration, and the slices and maps a ResultMarshaler may return
+	rVal := reflect.ValueOf(val)
+	switch rVal.Kind() {
+	case reflect.String:
+		return rVal.String(), nil
+	case reflect.Bool:
+		return rVal.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rVal.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rVal.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rVal.Float(), nil
+	case reflect.Pointer:
+		if rVal.IsNil() {
+			return nil, nil
+		}
+		return structValue(rVal.Elem().Interface(), path)
+	case reflect.Slice, reflect.Array:
+		if rVal.Kind() == reflect.Slice && rVal.IsNil() {
+			return nil, nil
+		}
+		elems := make([]interface{}, rVal.Len())
+		for i := range elems {
+			elems[i] = rVal.Index(i).Interface()
+		}
+		return structValue(elems, path)
+	case reflect.Map:
+		if rVal.IsNil() {
+			return nil, nil
+		}
+		entries := make(map[string]interface{}, rVal.Len())
+		iter := rVal.MapRange()
+		for iter.Next() {
+			key, ok := mapKey(iter.Key())
+			if !ok {
+				return nil, structError(path, fmt.Sprintf("unsupported map key type %s", rVal.Type().Key()))
+			}
+			entries[key] = iter.Value().Interface()
+		}
+		return structValue(entries, path)
+	}
+	return nil, structError(path, fmt.Sprintf("cannot convert %T", val))
+}
+
+// structError reports a value StructValues cannot convert, prefixed with its key path
+func structError(path, reason string) error {
+	if path != "" {
+		reason = path + ": " + reason
+	}
+	return errors.New("structpb: " + reason)
+}
@@ -0,0 +1,44 @@
+package jsonserilizer
+
+import "reflect"
+
+// remainInto merges the entries of a remain field into result, the keys of the other fields win
+func (o options) remainInto(result Result, remain reflect.Value) {
+	entries, ok := o.serializeValue(remain).(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key, val := range entries {
+		if _, ok := result[key]; !ok {
+			result[key] = val
+		}
+	}
+}
+
+// setRemain stores the keys of r no field consumed into the field of structVal tagged remain ex:
+// `serialize:",remain"`, and marks them consumed. The field is left untouched when every key was
+// matched.
+func (d *decoder) setRemain(r map[string]interface{}, structVal reflect.Value, prefix string, consumed map[string]bool) {
+	for i, field := range structFields(structVal.Type()) {
+		if !d.fieldInfo(field).remain {
+			continue
+		}
+		rest := make(map[string]interface{})
+		for key, val := range r {
+			if !consumed[key] && !(d.xml && key == xmlNameKey) {
+				rest[key] = val
+			}
+		}
+		if len(rest) == 0 {
+			return
+		}
+		d.setField(structVal.Field(i), rest, fieldPath(prefix, field.Name))
+		for key := range rest {
+			consumed[key] = true
+			if d.metadata != nil {
+				d.usedKeys = append(d.usedKeys, fieldPath(prefix, key))
+			}
+		}
+		return
+	}
+}
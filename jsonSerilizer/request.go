@@ -0,0 +1,95 @@
+//go:build !tinygo && !gofluentlite
+
+package jsonserilizer
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+// maxMultipartMemory is the memory multipart forms are parsed with, larger files go to disk
+const maxMultipartMemory = 32 << 20
+
+// defaultMaxBodySize bounds the request bodies BindRequest reads, like the forms of net/http
+const defaultMaxBodySize = 10 << 20
+
+// WithMaxBodySize bounds the bodies BindRequest reads to n bytes, 10 MiB by default, a negative n
+// lifts the bound. A larger body fails with an *http.MaxBytesError, to answer with 413 Request
+// Entity Too Large.
+func WithMaxBodySize(n int64) Option {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+// BindRequest populates the struct refOut points to from a request, decoded by its Content-Type:
+// the body of application/json and +json requests ex: application/merge-patch+json like Unmarshal,
+// the form of application/x-www-form-urlencoded and multipart/form-data requests like
+// DeserializeValues, and the query string of requests without a Content-Type ex: GET. Other content
+// types are reported as an error.
+func BindRequest(r *http.Request, refOut interface{}, opts ...Option) error {
+	result, opts, err := requestResult(r, opts)
+	if err != nil {
+		return err
+	}
+	return Deserialize(result, refOut, opts...)
+}
+
+// BindRequestValidated is BindRequest followed by validation with v like DeserializeValidated
+func BindRequestValidated(r *http.Request, refOut interface{}, v *validator.Validator, opts ...Option) error {
+	result, opts, err := requestResult(r, opts)
+	if err != nil {
+		return err
+	}
+	return DeserializeValidated(result, refOut, v, opts...)
+}
+
+// requestResult decodes the input of a request, opts gets the options of its format
+func requestResult(r *http.Request, opts []Option) (Result, []Option, error) {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return valuesResult(r.URL.Query()), append(opts, valuesOption), nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+
+	limit := newOptions(opts).maxBodySize
+	if limit == 0 {
+		limit = defaultMaxBodySize
+	}
+	if r.Body != nil && limit > 0 {
+		// the form parsers read r.Body themselves
+		r.Body = http.MaxBytesReader(nil, r.Body, limit)
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if r.Body == nil {
+			return nil, nil, fmt.Errorf("request has no body")
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+		result, err := decode(JSON, data, opts)
+		return result, opts, err
+	case mediaType == "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, nil, err
+		}
+	case mediaType == "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported content type %q", mediaType)
+	}
+	return valuesResult(r.PostForm), append(opts, valuesOption), nil
+}
@@ -0,0 +1,80 @@
+package jsonserilizer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// scalarField is a field of a struct holding only scalars, see scalarFields
+type scalarField struct {
+	index     int
+	key       string
+	omitEmpty bool
+}
+
+// scalarPlans holds the []scalarField of every struct type serialized, nil for the types holding
+// other values. RegisterTypeOptions empties it, the keys depending on the registered options.
+var scalarPlans sync.Map
+
+// scalarFields returns the fields to serialize of typ when they are all exported strings, booleans
+// and numbers without a representation of their own, ok is false otherwise and for the options
+// changing the keys. Such structs skip the checks serializeValue makes for every value.
+func (o options) scalarFields(typ reflect.Type) ([]scalarField, bool) {
+	if o.naming != nil || o.xml || o.bson || len(o.groups) > 0 {
+		return nil, false
+	}
+	if plan, ok := scalarPlans.Load(typ); ok {
+		fields := plan.([]scalarField)
+		return fields, fields != nil
+	}
+	fields := scalarPlan(typ)
+	scalarPlans.Store(typ, fields)
+	return fields, fields != nil
+}
+
+// scalarPlan lists the fields of typ serialized by a call without options, nil when one of them
+// is not a plain scalar
+func scalarPlan(typ reflect.Type) []scalarField {
+	fields := []scalarField{}
+	for i, field := range structFields(typ) {
+		info := options{}.fieldInfo(field)
+		if info.skip {
+			continue
+		}
+		if info.squash || info.remain || info.mask != "" || !isScalar(field.Type) {
+			return nil
+		}
+		fields = append(fields, scalarField{index: i, key: info.key, omitEmpty: info.omitEmpty})
+	}
+	return fields
+}
+
+// isScalar reports whether values of typ are strings, booleans or numbers serialized as they are
+func isScalar(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return false
+	}
+	for _, iface := range []reflect.Type{resultMarshalerType, marshalerType} {
+		if typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface) {
+			return false
+		}
+	}
+	return true
+}
+
+// serializeScalars converts the fields of a struct holding only scalars into a Result map
+func serializeScalars(rVal reflect.Value, fields []scalarField, omitEmpty bool) Result {
+	result := make(Result, len(fields))
+	for _, field := range fields {
+		val := rVal.Field(field.index)
+		if (field.omitEmpty || omitEmpty) && val.IsZero() {
+			continue
+		}
+		result[field.key] = val.Interface()
+	}
+	return result
+}
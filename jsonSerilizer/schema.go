@@ -0,0 +1,136 @@
+package jsonserilizer
+
+import "reflect"
+
+// Schema describes the Result of a struct type, for tools such as form generators or checks of
+// stored documents against the current struct
+type Schema struct {
+	// Type is the Go type ex: main.User
+	Type string
+	// Fields are the keys of the Result in field order, squashed fields in place
+	Fields []SchemaField
+}
+
+// SchemaField describes a key of a Result, or the elements of a list or map
+type SchemaField struct {
+	// Name is the Go field name, empty for elements
+	Name string
+	// Key is the key of the field in the Result, empty for elements
+	Key string
+	// Type is the Go type ex: []string or *time.Time
+	Type string
+	// Kind is the JSON kind of the value: string, integer, number, boolean, object, array, or any
+	// for interfaces and types choosing their own value ex: a ResultMarshaler
+	Kind string
+	// Nullable is set for pointers, slices, maps and interfaces, which can be null
+	Nullable bool
+	// OmitEmpty and Required are the tag options of the field
+	OmitEmpty bool
+	Required  bool
+	// Remain is set for the map collecting the keys no other field matches, see the remain option
+	Remain bool
+	// Tag holds every tag of the field ex: Tag.Get("validate")
+	Tag reflect.StructTag
+	// Fields describe the keys of a nested struct. They are left out for a struct type already
+	// being described, so a type holding itself ex: a linked list node ends the nesting.
+	Fields []SchemaField
+	// Elem describes the elements of a list or the values of a map
+	Elem *SchemaField
+}
+
+// InferSchema describes the struct type of v, a struct or a pointer to one which may be nil, with
+// the keys of Serialize for opts ex: WithNaming(SnakeCase). Other types give a Schema without fields.
+func InferSchema(v interface{}, opts ...Option) Schema {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return Schema{}
+	}
+	typ = structType(typ)
+	schema := Schema{Type: typ.String()}
+	if typ.Kind() == reflect.Struct {
+		schema.Fields = newOptions(opts).schemaFields(typ, nil)
+	}
+	return schema
+}
+
+// schemaFields describes the fields of typ, parents holds the struct types being described
+func (o options) schemaFields(typ reflect.Type, parents []reflect.Type) []SchemaField {
+	parents = append(parents, typ)
+	var fields []SchemaField
+	for _, field := range structFields(typ) {
+		info := o.fieldInfo(field)
+		switch {
+		case info.skip:
+		case info.squash:
+			fields = append(fields, o.schemaFields(structType(field.Type), parents)...)
+		default:
+			described := o.schemaType(field.Type, parents)
+			described.Name, described.Key, described.Tag = field.Name, info.key, field.Tag
+			described.OmitEmpty, described.Required, described.Remain = info.omitEmpty, info.required, info.remain
+			fields = append(fields, described)
+		}
+	}
+	return fields
+}
+
+// schemaType describes the values of typ in a Result
+func (o options) schemaType(typ reflect.Type, parents []reflect.Type) SchemaField {
+	described := SchemaField{Type: typ.String(), Kind: "any"}
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		described.Nullable = true
+	}
+	for _, iface := range []reflect.Type{resultMarshalerType, marshalerType} {
+		if typ.Implements(iface) || reflect.PointerTo(typ).Implements(iface) {
+			return described
+		}
+	}
+
+	base := structType(typ)
+	if base == timeType {
+		described.Kind = "string"
+		return described
+	}
+	switch base.Kind() {
+	case reflect.String:
+		described.Kind = "string"
+	case reflect.Bool:
+		described.Kind = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		described.Kind = "integer"
+	case reflect.Float32, reflect.Float64:
+		described.Kind = "number"
+	case reflect.Struct:
+		described.Kind = "object"
+		if !isSchemaParent(base, parents) {
+			described.Fields = o.schemaFields(base, parents)
+		}
+	case reflect.Slice, reflect.Array:
+		if base.Kind() == reflect.Slice && base.Elem().Kind() == reflect.Uint8 {
+			// []byte is written as base64 text
+			described.Kind = "string"
+			break
+		}
+		described.Kind = "array"
+		elem := o.schemaType(base.Elem(), parents)
+		described.Elem = &elem
+	case reflect.Map:
+		if _, ok := mapKey(reflect.New(base.Key()).Elem()); ok {
+			described.Kind = "object"
+			elem := o.schemaType(base.Elem(), parents)
+			described.Elem = &elem
+		}
+	}
+	return described
+}
+
+// isSchemaParent reports whether typ is one of the struct types being described
+func isSchemaParent(typ reflect.Type, parents []reflect.Type) bool {
+	for _, parent := range parents {
+		if parent == typ {
+			return true
+		}
+	}
+	return false
+}
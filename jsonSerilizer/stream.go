@@ -0,0 +1,103 @@
+package jsonserilizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// WithJSONArray makes EncodeStream write a single JSON array rather than newline delimited JSON
+func WithJSONArray() Option {
+	return func(o *options) {
+		o.jsonArray = true
+	}
+}
+
+// EncodeStream writes items, a slice, an array or a channel of structs or of pointers to structs,
+// as newline delimited JSON, or as a JSON array WithJSONArray. Items are serialized one at a time,
+// so exporting millions of records from a channel never holds them all in memory, and nil pointers
+// are written as null. Channels are read until they are closed.
+func EncodeStream(w io.Writer, items interface{}, opts ...Option) error {
+	rVal := reflect.ValueOf(items)
+	switch rVal.Kind() {
+	case reflect.Slice, reflect.Array:
+	case reflect.Chan:
+		if rVal.Type().ChanDir()&reflect.RecvDir == 0 {
+			return fmt.Errorf("EncodeStream requires a channel it can receive from, got %T", items)
+		}
+	default:
+		return fmt.Errorf("EncodeStream requires a slice, an array or a channel of structs, got %T", items)
+	}
+	if !isSquashable(rVal.Type().Elem()) {
+		return fmt.Errorf("EncodeStream requires a slice, an array or a channel of structs, got %T", items)
+	}
+
+	o := newOptions(opts)
+	out := bufio.NewWriter(w)
+	// lines of newline delimited JSON, or the opening, separator and end of an array
+	open, sep, end := "", "\n", "\n"
+	if o.jsonArray {
+		open, sep, end = "[", ",", "]\n"
+		if o.indent != "" || o.indentPrefix != "" {
+			open, sep, end = "[\n"+o.indentPrefix+o.indent, ",\n"+o.indentPrefix+o.indent, "\n"+o.indentPrefix+"]\n"
+			o.indentPrefix += o.indent
+		}
+	}
+	count := 0
+	for {
+		item, ok := streamItem(rVal, count)
+		if !ok {
+			break
+		}
+		if count == 0 {
+			out.WriteString(open)
+		} else {
+			out.WriteString(sep)
+		}
+		if err := o.encodeItem(out, item, count); err != nil {
+			return err
+		}
+		count++
+	}
+	switch {
+	case count > 0:
+		out.WriteString(end)
+	case o.jsonArray:
+		out.WriteString("[]\n")
+	}
+	return out.Flush()
+}
+
+// streamItem returns the item at index i of a slice or an array, or the next one of a channel
+func streamItem(items reflect.Value, i int) (reflect.Value, bool) {
+	if items.Kind() == reflect.Chan {
+		return items.Recv()
+	}
+	if i >= items.Len() {
+		return reflect.Value{}, false
+	}
+	return items.Index(i), true
+}
+
+// encodeItem writes the JSON of a single item, with a fresh cycle state per item
+func (o options) encodeItem(out *bufio.Writer, item reflect.Value, i int) error {
+	if item.Kind() == reflect.Pointer && item.IsNil() {
+		out.WriteString("null")
+		return nil
+	}
+	o.cycles = &cycleState{}
+	if o.orders != nil {
+		o.orders = make(map[uintptr]orderedKeys)
+	}
+	r, err := o.structResult("EncodeStream", item.Interface())
+	if err != nil {
+		return fmt.Errorf("item %d: %w", i, err)
+	}
+	data, err := o.encodeJSON(r)
+	if err != nil {
+		return fmt.Errorf("item %d: %w", i, err)
+	}
+	out.Write(data)
+	return nil
+}
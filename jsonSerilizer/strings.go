@@ -0,0 +1,58 @@
+package jsonserilizer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+// SerializeStrings converts a struct into string values keyed by the dotted paths of SerializeFlat,
+// for stores accepting only text such as Redis hashes, HTTP headers or SQS message attributes.
+// Numbers are written without exponent ex: 1000000, booleans as true and false, times with the
+// layout of their field, types implementing fmt.Stringer ex: time.Duration with their String
+// method and empty lists, maps or other values without a text form as JSON. Nil values are left out.
+func SerializeStrings(s interface{}, opts ...Option) map[string]string {
+	flat := SerializeFlat(s, opts...)
+	if flat == nil {
+		return nil
+	}
+	out := make(map[string]string, len(flat))
+	for key, val := range flat {
+		if text, ok := stringValue(val); ok {
+			out[key] = text
+		}
+	}
+	return out
+}
+
+// stringValue returns the text of a flattened value, ok is false for nil values
+func stringValue(val interface{}) (text string, ok bool) {
+	rVal := reflect.ValueOf(val)
+	for rVal.Kind() == reflect.Pointer || rVal.Kind() == reflect.Interface {
+		if rVal.IsNil() {
+			return "", false
+		}
+		rVal = rVal.Elem()
+	}
+	if !rVal.IsValid() {
+		return "", false
+	}
+	val = rVal.Interface()
+	switch val.(type) {
+	case string, float32, float64:
+		return textValue(val), true
+	case bool:
+		return strconv.FormatBool(val.(bool)), true
+	}
+	switch rVal.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+		if _, isStringer := val.(interface{ String() string }); !isStringer {
+			data, err := json.Marshal(val)
+			if err != nil {
+				return textValue(val), true
+			}
+			return string(data), true
+		}
+	}
+	return textValue(val), true
+}
@@ -0,0 +1,73 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// validateTag is the struct tag read by the validator package
+const validateTag = "validate"
+
+// SuggestRules derives a baseline rule set for the struct type of v, a struct or a pointer to one
+// which may be nil, as a starting point for legacy structs without validate tags. Rules are given in
+// tag syntax by Go field path ex: Address.City, the paths of validation errors:
+//   - non pointer strings, time.Time and other fields holding a value of their own are required
+//   - numeric fields only get numeric rules: their zero is a valid value, so they are not required,
+//     and integers of 32 bits or less get the range of their type to narrow ex: range=0:255
+//   - booleans, pointers, slices, maps and interfaces get no rule, false and nil being valid values
+//   - nested structs, pointed to or not, are described field by field
+//
+// Fields with a validate tag keep their own rules and are left out, as well as fields skipped by
+// Serialize. Other types give no rules.
+func SuggestRules(v interface{}, opts ...Option) map[string]string {
+	suggested := make(map[string]string)
+	typ := reflect.TypeOf(v)
+	if typ == nil || structType(typ).Kind() != reflect.Struct {
+		return suggested
+	}
+	newOptions(opts).suggestRules(structType(typ), "", nil, suggested)
+	return suggested
+}
+
+// suggestRules adds the rules of the fields of typ to suggested, parents holds the struct types
+// being described so a type holding itself ex: a linked list node ends the nesting
+func (o options) suggestRules(typ reflect.Type, prefix string, parents []reflect.Type, suggested map[string]string) {
+	parents = append(parents, typ)
+	for _, field := range structFields(typ) {
+		info := o.fieldInfo(field)
+		if info.skip || info.remain {
+			continue
+		}
+		if _, tagged := field.Tag.Lookup(validateTag); tagged {
+			continue
+		}
+		path := field.Name
+		if prefix != "" {
+			path = prefix + "." + field.Name
+		}
+		if info.squash || isNestedStruct(field.Type, parents) {
+			o.suggestRules(structType(field.Type), path, parents, suggested)
+			continue
+		}
+		if rule := suggestedRule(field.Type); rule != "" {
+			suggested[path] = rule
+		}
+	}
+}
+
+// suggestedRule returns the baseline rule of a field of type typ, empty for none
+func suggestedRule(typ reflect.Type) string {
+	switch typ.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		bits := typ.Bits()
+		return fmt.Sprintf("range=%d:%d", -1<<(bits-1), 1<<(bits-1)-1)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return fmt.Sprintf("range=0:%d", uint64(math.MaxUint64)>>(64-typ.Bits()))
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.Uintptr, reflect.Float32, reflect.Float64:
+		return ""
+	case reflect.Bool, reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+		return ""
+	}
+	return "required"
+}
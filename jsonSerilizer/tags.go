@@ -0,0 +1,117 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+// serializeTag names the key of a field like the json tag ex: `json:"name,omitempty"` and wins over it, so a Result can differ from
+// the wire JSON of encoding/json ex: `json:"id" serialize:"user_id"` or `json:"token" serialize:"-"`
+const serializeTag = "serialize"
+
+// aliasesTag lists former keys of a field Deserialize still accepts ex: `aliases:"full_name,username"`
+const aliasesTag = "aliases"
+
+// defaultTag is the struct tag giving the value of a field whose key is missing ex: `default:"8080"`
+const defaultTag = "default"
+
+// fieldInfo is what the serialize or json tag of a field says about its key
+type fieldInfo struct {
+	key       string
+	omitEmpty bool
+	skip      bool
+	// tagged is set when the tag names the key
+	tagged bool
+	// squash merges the fields of a struct field into the parent map, see isSquashable
+	squash bool
+	// aliases are the keys read when key is missing, in order
+	aliases []string
+	// remain collects the keys no other field matches, see isRemainable
+	remain bool
+	// mask hides the value of a sensitive field in Serialize, see validator.FieldMask
+	mask string
+	// timeFormat is the layout of time.Time values, from the time_format tag
+	timeFormat string
+	// required keys must be present, see WithRequiredKeys
+	required bool
+	// defaultValue, from the default tag, is applied when the key is missing and hasDefault is set
+	defaultValue string
+	hasDefault   bool
+	// groups are the views of the field, from the serialize_groups tag, see WithGroups
+	groups []string
+}
+
+// parseField reads the serialize tag of field, or else its json tag. Without a name the Go field
+// name is the key, "-" skips the field and "-," uses - as the key, like encoding/json.
+// Unexported fields are skipped, reflect can neither read nor set them, except embedded structs
+// tagged squash whose exported fields are promoted.
+func parseField(field structwalk.Field) fieldInfo {
+	info := fieldInfo{key: field.Name, timeFormat: field.Tag.Get(timeFormatTag), mask: validator.FieldMask(field.StructField)}
+	info.defaultValue, info.hasDefault = field.Tag.Lookup(defaultTag)
+	info.groups = parseGroups(field.Tag.Get(groupsTag))
+	for _, alias := range strings.Split(field.Tag.Get(aliasesTag), ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			info.aliases = append(info.aliases, alias)
+		}
+	}
+	name, opts, skip := field.JSONName, field.JSONOptions, field.JSONSkip
+	tag, ok := field.Tag.Lookup(serializeTag)
+	if ok {
+		name, opts, skip = structwalk.ParseTag(tag)
+	}
+	if skip {
+		return fieldInfo{skip: true}
+	}
+	if ok || field.JSONName != "" || len(field.JSONOptions) > 0 {
+		if name != "" {
+			info.key, info.tagged = name, true
+		}
+		for _, opt := range opts {
+			switch opt {
+			case "omitempty":
+				info.omitEmpty = true
+			case "squash":
+				info.squash = isSquashable(field.Type)
+			case "remain":
+				info.remain = isRemainable(field.Type)
+			case "required":
+				info.required = true
+			}
+		}
+	}
+
+	if !field.IsExported() && !(field.Promoted && info.squash) {
+		return fieldInfo{skip: true}
+	}
+	return info
+}
+
+// isRemainable reports whether a field of typ can collect the keys left over by the other fields,
+// maps with string keys ex: map[string]interface{}
+func isRemainable(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String
+}
+
+// isSquashable reports whether the fields of a value of typ can be merged into its parent,
+// structs and pointers to structs
+func isSquashable(typ reflect.Type) bool {
+	return structwalk.IsStruct(typ)
+}
+
+// maskValue hides a serialized value of a field tagged sensitive or mask ex: `mask:"last4"`. Scalars
+// are masked as text, lists and objects as a whole, and nil or empty values are kept as they are.
+func maskValue(val interface{}, mask string) interface{} {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case string:
+		return validator.Mask(v, mask)
+	case []interface{}, map[string]interface{}, Result:
+		return validator.Mask(fmt.Sprint(v), "")
+	}
+	return validator.Mask(textValue(val), mask)
+}
@@ -0,0 +1,51 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// timeFormatTag is the struct tag giving the layout of a time.Time field ex: `time_format:"2006-01-02"`
+const timeFormatTag = "time_format"
+
+// defaultTimeLayout is used for time.Time values without a time_format tag, RFC3339 with the
+// fractional seconds when there are some, like encoding/json
+const defaultTimeLayout = time.RFC3339Nano
+
+// timeType is the reflect.Type of time.Time
+var timeType = reflect.TypeOf(time.Time{})
+
+// durationType is the reflect.Type of time.Duration, also read from strings such as 1m30s
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// layout returns the time layout of the current field, or else the one registered for time.Time
+func (o options) layout() string {
+	if o.timeLayout != "" {
+		return o.timeLayout
+	}
+	if layout := registeredLayout(); layout != "" {
+		return layout
+	}
+	return defaultTimeLayout
+}
+
+// parseTime stores text parsed with the layout of the current field in a time.Time field
+func (d *decoder) parseTime(field reflect.Value, text string, path string) {
+	t, err := time.Parse(d.layout(), text)
+	if err != nil {
+		d.fail(path, field, text, fmt.Sprintf("cannot parse %q as %s", text, d.layout()))
+		return
+	}
+	field.Set(reflect.ValueOf(t))
+}
+
+// parseDuration stores text parsed by time.ParseDuration in a time.Duration field
+func (d *decoder) parseDuration(field reflect.Value, text string, path string) {
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		d.fail(path, field, text, fmt.Sprintf("cannot parse %q as a duration", text))
+		return
+	}
+	field.SetInt(int64(duration))
+}
@@ -0,0 +1,695 @@
+package jsonserilizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TOML reads and writes TOML documents, such as configuration files. Dates and times are kept as
+// their text, which time.Time fields parse like JSON strings, and null values are left out.
+var TOML Codec = tomlCodec{}
+
+type tomlCodec struct{}
+
+func (tomlCodec) Encode(r Result) ([]byte, error) {
+	var enc tomlEncoder
+	if err := enc.table(nil, plainValue(r).(map[string]interface{})); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimPrefix(enc.out.String(), "\n")), nil
+}
+
+func (tomlCodec) Decode(data []byte) (Result, error) {
+	p := &tomlParser{src: string(data), root: make(map[string]interface{})}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.root, nil
+}
+
+// tomlEncoder writes a Result as TOML, plain keys first then one [table] per nested map and
+// one [[table]] per element of arrays of maps
+type tomlEncoder struct {
+	out strings.Builder
+}
+
+// table writes the keys of m, path is the key path of its header, nil for the document root
+func (e *tomlEncoder) table(path []string, m map[string]interface{}) error {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		val := m[key]
+		if val == nil || isTable(val) || isTableArray(val) {
+			continue
+		}
+		text, err := e.value(val)
+		if err != nil {
+			return fmt.Errorf("toml: %s: %w", strings.Join(append(path, key), "."), err)
+		}
+		fmt.Fprintf(&e.out, "%s = %s\n", tomlKey(key), text)
+	}
+
+	for _, key := range keys {
+		childPath := append(append([]string(nil), path...), key)
+		switch val := m[key].(type) {
+		case map[string]interface{}:
+			fmt.Fprintf(&e.out, "\n[%s]\n", tomlKeyPath(childPath))
+			if err := e.table(childPath, val); err != nil {
+				return err
+			}
+		case []interface{}:
+			if !isTableArray(val) {
+				continue
+			}
+			for _, elem := range val {
+				fmt.Fprintf(&e.out, "\n[[%s]]\n", tomlKeyPath(childPath))
+				if err := e.table(childPath, elem.(map[string]interface{})); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// value returns the inline TOML text of val
+func (e *tomlEncoder) value(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", errors.New("null cannot be written inside an array")
+	case string:
+		return tomlString(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if v[key] != nil {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, key := range keys {
+			text, err := e.value(v[key])
+			if err != nil {
+				return "", err
+			}
+			pairs[i] = tomlKey(key) + " = " + text
+		}
+		if len(pairs) == 0 {
+			return "{}", nil
+		}
+		return "{ " + strings.Join(pairs, ", ") + " }", nil
+	case []interface{}:
+		elems := make([]string, len(v))
+		for i, elem := range v {
+			text, err := e.value(elem)
+			if err != nil {
+				return "", err
+			}
+			elems[i] = text
+		}
+		return "[" + strings.Join(elems, ", ") + "]", nil
+	}
+
+	// named types ex: time.Duration or an enum
+	rVal := reflect.ValueOf(val)
+	switch rVal.Kind() {
+	case reflect.String:
+		return tomlString(rVal.String()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(rVal.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rVal.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rVal.Uint() > math.MaxInt64 {
+			return "", fmt.Errorf("%d overflows a TOML integer", rVal.Uint())
+		}
+		return strconv.FormatUint(rVal.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return tomlFloat(rVal.Float()), nil
+	}
+	return "", fmt.Errorf("cannot write %T", val)
+}
+
+// isTable reports whether val is written as a [table]
+func isTable(val interface{}) bool {
+	_, ok := val.(map[string]interface{})
+	return ok
+}
+
+// isTableArray reports whether val is a non empty array of maps, written as [[table]] sections
+func isTableArray(val interface{}) bool {
+	elems, ok := val.([]interface{})
+	if !ok || len(elems) == 0 {
+		return false
+	}
+	for _, elem := range elems {
+		if !isTable(elem) {
+			return false
+		}
+	}
+	return true
+}
+
+// tomlKeyPath joins the keys of a table header ex: server."web admin"
+func tomlKeyPath(path []string) string {
+	keys := make([]string, len(path))
+	for i, key := range path {
+		keys[i] = tomlKey(key)
+	}
+	return strings.Join(keys, ".")
+}
+
+// tomlKey writes a key bare when TOML allows it, quoted otherwise
+func tomlKey(key string) string {
+	if key == "" {
+		return `""`
+	}
+	for _, c := range key {
+		if !isBareKeyChar(c) {
+			return tomlString(key)
+		}
+	}
+	return key
+}
+
+// tomlString quotes s as a TOML basic string
+func tomlString(s string) string {
+	var out strings.Builder
+	out.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"':
+			out.WriteString(`\"`)
+		case '\\':
+			out.WriteString(`\\`)
+		case '\b':
+			out.WriteString(`\b`)
+		case '\t':
+			out.WriteString(`\t`)
+		case '\n':
+			out.WriteString(`\n`)
+		case '\f':
+			out.WriteString(`\f`)
+		case '\r':
+			out.WriteString(`\r`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&out, `\u%04X`, c)
+			} else {
+				out.WriteRune(c)
+			}
+		}
+	}
+	out.WriteByte('"')
+	return out.String()
+}
+
+// tomlFloat writes f so it is read back as a float ex: 2.0 rather than 2
+func tomlFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	return text
+}
+
+func isBareKeyChar(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// tomlParser reads a TOML document into nested maps, integers as int64 and floats as float64
+type tomlParser struct {
+	src  string
+	pos  int
+	root map[string]interface{}
+	// depth is the nesting of the array or inline table being read, see tomlMaxDepth
+	depth int
+}
+
+// tomlMaxDepth bounds the nesting of arrays and inline tables
+const tomlMaxDepth = 10000
+
+// errorf reports a syntax error at the current line
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	return fmt.Errorf("toml: line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+func (p *tomlParser) parse() error {
+	current := p.root
+	headers := make(map[string]bool)
+	for {
+		p.skipBlank(true)
+		if p.pos >= len(p.src) {
+			return nil
+		}
+
+		switch {
+		case strings.HasPrefix(p.src[p.pos:], "[["):
+			p.pos += 2
+			path, err := p.keyPath()
+			if err != nil {
+				return err
+			}
+			if !p.consume("]]") {
+				return p.errorf("expected ]] after the table name")
+			}
+			current, err = p.appendTable(path)
+			if err != nil {
+				return err
+			}
+		case p.src[p.pos] == '[':
+			p.pos++
+			path, err := p.keyPath()
+			if err != nil {
+				return err
+			}
+			if !p.consume("]") {
+				return p.errorf("expected ] after the table name")
+			}
+			name := strings.Join(path, "\x00")
+			if headers[name] {
+				return p.errorf("table %s is defined twice", strings.Join(path, "."))
+			}
+			headers[name] = true
+			current, err = p.descend(p.root, path)
+			if err != nil {
+				return err
+			}
+		default:
+			if err := p.keyValue(current); err != nil {
+				return err
+			}
+		}
+
+		p.skipBlank(false)
+		if p.pos < len(p.src) && !p.consume("\n") && !p.consume("\r\n") {
+			return p.errorf("expected a new line, got %q", p.src[p.pos])
+		}
+	}
+}
+
+// keyValue reads key = value into table
+func (p *tomlParser) keyValue(table map[string]interface{}) error {
+	path, err := p.keyPath()
+	if err != nil {
+		return err
+	}
+	p.skipBlank(false)
+	if !p.consume("=") {
+		return p.errorf("expected = after key %s", strings.Join(path, "."))
+	}
+	p.skipBlank(false)
+	val, err := p.value()
+	if err != nil {
+		return err
+	}
+
+	parent, err := p.descend(table, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	key := path[len(path)-1]
+	if _, exists := parent[key]; exists {
+		return p.errorf("key %s is defined twice", strings.Join(path, "."))
+	}
+	parent[key] = val
+	return nil
+}
+
+// descend returns the table at path below table, creating the missing ones. The last element of an
+// array of tables is used, as TOML does for headers below [[name]].
+func (p *tomlParser) descend(table map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for _, key := range path {
+		switch next := table[key].(type) {
+		case nil:
+			child := make(map[string]interface{})
+			table[key] = child
+			table = child
+		case map[string]interface{}:
+			table = next
+		case []interface{}:
+			last, ok := next[len(next)-1].(map[string]interface{})
+			if !ok || !isTableArray(next) {
+				return nil, p.errorf("key %s is not a table", key)
+			}
+			table = last
+		default:
+			return nil, p.errorf("key %s is not a table", key)
+		}
+	}
+	return table, nil
+}
+
+// appendTable adds a table to the array of tables at path and returns it
+func (p *tomlParser) appendTable(path []string) (map[string]interface{}, error) {
+	parent, err := p.descend(p.root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	key := path[len(path)-1]
+	table := make(map[string]interface{})
+	switch existing := parent[key].(type) {
+	case nil:
+		parent[key] = []interface{}{table}
+	case []interface{}:
+		if !isTableArray(existing) {
+			return nil, p.errorf("key %s is not an array of tables", key)
+		}
+		parent[key] = append(existing, table)
+	default:
+		return nil, p.errorf("key %s is not an array of tables", key)
+	}
+	return table, nil
+}
+
+// keyPath reads a dotted key ex: server."web admin".port
+func (p *tomlParser) keyPath() ([]string, error) {
+	var path []string
+	for {
+		p.skipBlank(false)
+		key, err := p.key()
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, key)
+		p.skipBlank(false)
+		if !p.consume(".") {
+			return path, nil
+		}
+	}
+}
+
+// key reads a bare, basic or literal key
+func (p *tomlParser) key() (string, error) {
+	if p.pos < len(p.src) && (p.src[p.pos] == '"' || p.src[p.pos] == '\'') {
+		return p.quoted()
+	}
+	start := p.pos
+	for p.pos < len(p.src) && isBareKeyChar(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected a key")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// value reads a string, number, boolean, date, array or inline table
+func (p *tomlParser) value() (interface{}, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '"' || c == '\'':
+		return p.quoted()
+	case (c == '[' || c == '{') && p.depth == tomlMaxDepth:
+		return nil, p.errorf("arrays and inline tables nested deeper than %d levels", tomlMaxDepth)
+	case c == '[':
+		p.depth++
+		defer func() { p.depth-- }()
+		return p.array()
+	case c == '{':
+		p.depth++
+		defer func() { p.depth-- }()
+		return p.inlineTable()
+	case p.consume("true"):
+		return true, nil
+	case p.consume("false"):
+		return false, nil
+	}
+	return p.scalar()
+}
+
+// quoted reads a basic, literal or multi-line string
+func (p *tomlParser) quoted() (string, error) {
+	quote := p.src[p.pos : p.pos+1]
+	multiLine := strings.HasPrefix(p.src[p.pos:], strings.Repeat(quote, 3))
+	delim := quote
+	if multiLine {
+		delim = strings.Repeat(quote, 3)
+		p.pos += 3
+		// a new line right after the opening delimiter is trimmed
+		if !p.consume("\n") {
+			p.consume("\r\n")
+		}
+	} else {
+		p.pos++
+	}
+
+	var out strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			return "", p.errorf("unterminated string")
+		}
+		if strings.HasPrefix(p.src[p.pos:], delim) {
+			if !multiLine {
+				p.pos++
+				return out.String(), nil
+			}
+			// up to two quotes may precede the closing delimiter ex: """say "hi""""
+			run := 0
+			for p.pos+run < len(p.src) && p.src[p.pos+run] == quote[0] {
+				run++
+			}
+			if run > 5 {
+				return "", p.errorf("too many quotes closing a multi-line string")
+			}
+			out.WriteString(strings.Repeat(quote, run-3))
+			p.pos += run
+			return out.String(), nil
+		}
+
+		c := p.src[p.pos]
+		switch {
+		case c == '\n' && !multiLine:
+			return "", p.errorf("new line in a single line string")
+		case c == '\\' && quote == `"`:
+			if err := p.escape(&out, multiLine); err != nil {
+				return "", err
+			}
+		default:
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			out.WriteRune(r)
+			p.pos += size
+		}
+	}
+}
+
+// escape reads an escape sequence of a basic string
+func (p *tomlParser) escape(out *strings.Builder, multiLine bool) error {
+	p.pos++
+	if p.pos >= len(p.src) {
+		return p.errorf("unterminated string")
+	}
+	c := p.src[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		out.WriteByte('\b')
+	case 't':
+		out.WriteByte('\t')
+	case 'n':
+		out.WriteByte('\n')
+	case 'f':
+		out.WriteByte('\f')
+	case 'r':
+		out.WriteByte('\r')
+	case 'e':
+		out.WriteByte(0x1b)
+	case '"':
+		out.WriteByte('"')
+	case '\\':
+		out.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return p.errorf("invalid unicode escape")
+		}
+		code, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorf("invalid unicode escape \\%c%s", c, p.src[p.pos:p.pos+size])
+		}
+		out.WriteRune(rune(code))
+		p.pos += size
+	default:
+		// a backslash ending a line of a multi-line string trims the following blanks
+		if multiLine && (c == ' ' || c == '\t' || c == '\n' || c == '\r') {
+			p.pos--
+			for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+				p.pos++
+			}
+			return nil
+		}
+		return p.errorf("invalid escape \\%c", c)
+	}
+	return nil
+}
+
+// array reads [a, b, c], which may span lines and hold comments
+func (p *tomlParser) array() ([]interface{}, error) {
+	p.pos++
+	elems := []interface{}{}
+	for {
+		p.skipBlank(true)
+		if p.consume("]") {
+			return elems, nil
+		}
+		elem, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+		p.skipBlank(true)
+		if p.consume("]") {
+			return elems, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or ] in an array")
+		}
+	}
+}
+
+// inlineTable reads { key = value, ... }
+func (p *tomlParser) inlineTable() (map[string]interface{}, error) {
+	p.pos++
+	table := make(map[string]interface{})
+	for {
+		p.skipBlank(false)
+		if p.consume("}") {
+			return table, nil
+		}
+		if err := p.keyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipBlank(false)
+		if p.consume("}") {
+			return table, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or } in an inline table")
+		}
+	}
+}
+
+// scalar reads a number or a date, dates are returned as their text
+func (p *tomlParser) scalar() (interface{}, error) {
+	start := p.pos
+	for p.pos < len(p.src) && isScalarChar(p.src[p.pos]) {
+		p.pos++
+	}
+	// a space may separate the date and the time ex: 1979-05-27 07:32:00Z
+	if p.pos-start == 10 && p.src[start+4] == '-' && p.pos+1 < len(p.src) && p.src[p.pos] == ' ' && isDigit(p.src[p.pos+1]) {
+		p.pos++
+		for p.pos < len(p.src) && isScalarChar(p.src[p.pos]) {
+			p.pos++
+		}
+	}
+	text := p.src[start:p.pos]
+	if text == "" {
+		return nil, p.errorf("expected a value")
+	}
+
+	switch strings.TrimLeft(text, "+-") {
+	case "inf":
+		if text[0] == '-' {
+			return math.Inf(-1), nil
+		}
+		return math.Inf(1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	if len(text) >= 5 && text[4] == '-' || len(text) >= 3 && text[2] == ':' {
+		return strings.Replace(text, " ", "T", 1), nil
+	}
+
+	digits := strings.ReplaceAll(text, "_", "")
+	if strings.Contains(text, "__") || strings.HasPrefix(text, "_") || strings.HasSuffix(text, "_") {
+		return nil, p.errorf("invalid number %q", text)
+	}
+	if len(digits) > 2 && digits[0] == '0' {
+		base := map[byte]int{'x': 16, 'o': 8, 'b': 2}[digits[1]]
+		if base != 0 {
+			n, err := strconv.ParseInt(digits[2:], base, 64)
+			if err != nil {
+				return nil, p.errorf("invalid number %q", text)
+			}
+			return n, nil
+		}
+	}
+	if strings.ContainsAny(digits, ".eE") {
+		f, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", text)
+		}
+		return f, nil
+	}
+	unsigned := strings.TrimLeft(digits, "+-")
+	if len(unsigned) > 1 && unsigned[0] == '0' {
+		return nil, p.errorf("invalid number %q, leading zeros are not allowed", text)
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", text)
+	}
+	return n, nil
+}
+
+func isScalarChar(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || strings.IndexByte("_+-.:", c) >= 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// skipBlank skips spaces, tabs and comments, and new lines too when newLines is set
+func (p *tomlParser) skipBlank(newLines bool) {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case newLines && (c == '\n' || c == '\r'):
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume advances past token when the input continues with it
+func (p *tomlParser) consume(token string) bool {
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SerializeAs is Serialize for a struct type, or a pointer to a struct type, known at compile time.
+// Go cannot constrain T to structs, other types are reported as an error instead of a nil Result.
+func SerializeAs[T any](v T, opts ...Option) (Result, error) {
+	return structResult("SerializeAs", v, opts)
+}
+
+// DeserializeAs returns a new T populated from r like Deserialize ex: DeserializeAs[User](r).
+// T is a struct type or a pointer to a struct type, which is allocated.
+func DeserializeAs[T any](r Result, opts ...Option) (T, error) {
+	var out T
+	rVal := reflect.ValueOf(&out).Elem()
+	if rVal.Kind() == reflect.Pointer && rVal.Type().Elem().Kind() == reflect.Struct {
+		rVal.Set(reflect.New(rVal.Type().Elem()))
+		rVal = rVal.Elem()
+	}
+	if rVal.Kind() != reflect.Struct {
+		return out, fmt.Errorf("DeserializeAs requires a struct type, got %s", reflect.TypeOf(&out).Elem())
+	}
+	err := Deserialize(r, rVal.Addr().Interface(), opts...)
+	return out, err
+}
@@ -0,0 +1,73 @@
+package jsonserilizer
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Options are the serializer settings of a type, see RegisterTypeOptions
+type Options struct {
+	// TimeLayout formats and parses the time.Time fields of a struct type, or every time.Time value
+	// when registered for time.Time itself
+	TimeLayout string
+	// Naming derives the keys of the untagged fields of a struct type
+	Naming NamingStrategy
+	// OmitEmpty leaves the fields holding a zero value of the type out of the output
+	OmitEmpty bool
+}
+
+// typeOptions holds the Options registered by type, see RegisterTypeOptions
+var typeOptions sync.Map
+
+// RegisterTypeOptions makes every Serialize and Deserialize call treat typ with opts ex: a Money type
+// left out when zero, or RFC1123 timestamps across the whole application, without repeating tags:
+//
+//	RegisterTypeOptions(reflect.TypeOf(time.Time{}), Options{TimeLayout: time.RFC1123})
+//	RegisterTypeOptions(reflect.TypeOf(Money{}), Options{Naming: SnakeCase, OmitEmpty: true})
+//
+// Struct tags win over the registered options, which win over the options of the call such as
+// WithNaming. A later registration replaces an earlier one for the same type.
+func RegisterTypeOptions(typ reflect.Type, opts Options) {
+	if typ == nil {
+		panic("jsonserilizer: RegisterTypeOptions expects a type")
+	}
+	typeOptions.Store(typ, opts)
+	scalarPlans.Range(func(typ, _ interface{}) bool {
+		scalarPlans.Delete(typ)
+		return true
+	})
+}
+
+// registeredOptions returns the Options registered for typ
+func registeredOptions(typ reflect.Type) (Options, bool) {
+	opts, ok := typeOptions.Load(typ)
+	if !ok {
+		return Options{}, false
+	}
+	return opts.(Options), true
+}
+
+// typeFieldInfo applies to the info of field the Options registered for the struct holding it and
+// for its type, a pointer to a registered type included, and returns the naming of its key: the
+// one registered for the struct, or else naming
+func typeFieldInfo(field cachedField, naming NamingStrategy) (fieldInfo, NamingStrategy) {
+	info := field.info
+	if owner, ok := registeredOptions(field.Owner); ok {
+		if owner.Naming != nil {
+			naming = owner.Naming
+		}
+		if info.timeFormat == "" {
+			info.timeFormat = owner.TimeLayout
+		}
+	}
+	if own, ok := registeredOptions(structType(field.Type)); ok && own.OmitEmpty {
+		info.omitEmpty = true
+	}
+	return info, naming
+}
+
+// registeredLayout returns the TimeLayout registered for time.Time, empty for none
+func registeredLayout() string {
+	opts, _ := registeredOptions(timeType)
+	return opts.TimeLayout
+}
@@ -0,0 +1,100 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// typeKey holds the registered name of the concrete type of an interface field ex:
+// {"$type": "CreditCardPayment", "Number": "..."}
+const typeKey = "$type"
+
+var (
+	typesMu     sync.RWMutex
+	typesByName = make(map[string]reflect.Type)
+	namesByType = make(map[reflect.Type]string)
+)
+
+// RegisterType makes the struct type of prototype usable in interface fields under name ex:
+// RegisterType("CreditCardPayment", CreditCardPayment{}). Serialize stores the name of a registered
+// value under the $type key of its map, and Deserialize reads it to rebuild the concrete type,
+// a pointer to it when only the pointer implements the interface of the field.
+func RegisterType(name string, prototype interface{}) {
+	structType := reflect.TypeOf(prototype)
+	if structType != nil && structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("jsonserilizer: RegisterType %s expects a struct, got %T", name, prototype))
+	}
+
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	typesByName[name] = structType
+	namesByType[structType] = name
+}
+
+// serializeTyped serializes the registered struct held by an interface value, or a pointer to it,
+// with its type name, ok is false for other values
+func (o options) serializeTyped(val reflect.Value) (interface{}, bool) {
+	if val.IsNil() {
+		return nil, false
+	}
+	elem := val.Elem()
+	structType := elem.Type()
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	typesMu.RLock()
+	name, ok := namesByType[structType]
+	typesMu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	out := o.serializeValue(elem)
+	if r, isMap := out.(Result); isMap {
+		// nil pointers and types choosing their own representation are kept without a name
+		r[typeKey] = name
+	}
+	return out, true
+}
+
+// setTyped populates an interface field from a map naming its concrete type under $type, handled is
+// false for values without one
+func (d *decoder) setTyped(field reflect.Value, val interface{}, path string) (handled bool) {
+	m, ok := asMap(val)
+	if !ok || field.Kind() != reflect.Interface {
+		return false
+	}
+	name, ok := m[typeKey].(string)
+	if !ok {
+		return false
+	}
+	typesMu.RLock()
+	structType, ok := typesByName[name]
+	typesMu.RUnlock()
+	if !ok {
+		d.fail(path, field, val, fmt.Sprintf("unknown type %q, register it with RegisterType", name))
+		return true
+	}
+
+	fields := make(map[string]interface{}, len(m))
+	for key, fieldVal := range m {
+		if key != typeKey {
+			fields[key] = fieldVal
+		}
+	}
+	ptr := reflect.New(structType)
+	d.deserializeObject(fields, ptr.Elem(), path)
+	switch {
+	case structType.AssignableTo(field.Type()):
+		field.Set(ptr.Elem())
+	case ptr.Type().AssignableTo(field.Type()):
+		field.Set(ptr)
+	default:
+		d.fail(path, field, val, fmt.Sprintf("%s does not implement %s", structType, field.Type()))
+	}
+	return true
+}
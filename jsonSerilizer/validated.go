@@ -0,0 +1,34 @@
+package jsonserilizer
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+// DeserializeValidated populates the struct refOut points to like Deserialize, then validates it with
+// v, or validator.Default() when v is nil. Fields that cannot be stored do not prevent validation of
+// the others: the errors of both steps are joined, so errors.As finds the FieldTypeErrors with their
+// field paths as well as the validator.ValidationErrors.
+func DeserializeValidated(r Result, refOut interface{}, v *validator.Validator, opts ...Option) error {
+	err := Deserialize(r, refOut, opts...)
+	rVal := reflect.ValueOf(refOut)
+	if rVal.Kind() != reflect.Pointer || rVal.IsNil() || rVal.Elem().Kind() != reflect.Struct {
+		return err
+	}
+	if v == nil {
+		v = validator.Default()
+	}
+	return errors.Join(err, v.Validate(refOut))
+}
+
+// UnmarshalValidated parses a JSON object into the struct out points to and validates it like
+// DeserializeValidated, invalid JSON is returned without validating
+func UnmarshalValidated(data []byte, out interface{}, v *validator.Validator, opts ...Option) error {
+	r, err := decode(JSON, data, opts)
+	if err != nil {
+		return err
+	}
+	return DeserializeValidated(r, out, v, opts...)
+}
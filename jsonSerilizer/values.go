@@ -0,0 +1,114 @@
+package jsonserilizer
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DeserializeValues populates a struct from query string or form values ex: r.URL.Query() or
+// r.PostForm, looking fields up by the keys of Serialize. Values are converted to the type of their
+// field like WithStringCoercion, repeated keys ex: tags=a&tags=b or tags[]=a&tags[]=b fill slices,
+// dotted keys ex: address.city fill nested structs and HTML checkboxes sending on populate bool
+// fields. Empty values, as sent by blank form inputs, leave their field unset.
+func DeserializeValues(values url.Values, refOut interface{}, opts ...Option) error {
+	return Deserialize(valuesResult(values), refOut, append(opts, valuesOption)...)
+}
+
+// valuesOption reads the conventions of query strings and forms, and their numbers and booleans
+func valuesOption(o *options) {
+	o.values = true
+	o.stringCoercion = true
+}
+
+// valuesResult builds the Result of query string or form values, nested by their dotted keys
+func valuesResult(values url.Values) Result {
+	// sorted keys keep the outcome of conflicting keys ex: address and address.city stable
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	r := make(Result)
+	for _, key := range keys {
+		vals := values[key]
+		var val interface{}
+		switch {
+		case len(vals) == 0 || len(vals) == 1 && vals[0] == "":
+			continue
+		case len(vals) == 1:
+			val = vals[0]
+		default:
+			elems := make([]interface{}, len(vals))
+			for i, v := range vals {
+				elems[i] = v
+			}
+			val = elems
+		}
+		setPath(r, strings.Split(strings.TrimSuffix(key, "[]"), "."), val)
+	}
+	return r
+}
+
+// valuesValue adapts a form value to field: a single value is a list of one for slices, and the on
+// and off of checkboxes are booleans
+func valuesValue(field reflect.Value, val interface{}) interface{} {
+	val = singleElement(field, val)
+	if field.Kind() == reflect.Bool {
+		switch val {
+		case "on":
+			return "true"
+		case "off":
+			return "false"
+		}
+	}
+	return val
+}
+
+// EncodeQuery returns the query string values of a struct, or of a pointer to a struct, with the keys
+// of Serialize so naming strategies and omitempty apply ex: to build signed request URLs. Nested
+// structs get dotted keys ex: address.city, slices repeat their key and nil values are left out,
+// which DeserializeValues reads back. Values.Encode sorts the keys, giving a stable text to sign.
+func EncodeQuery(v interface{}, opts ...Option) (url.Values, error) {
+	r, err := structResult("EncodeQuery", v, opts)
+	if err != nil {
+		return nil, err
+	}
+	values := make(url.Values)
+	if err := addValues(values, "", plainValue(r)); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// addValues adds val to values under key, flattening maps with dotted keys and repeating the key
+// of lists
+func addValues(values url.Values, key string, val interface{}) error {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		for nestedKey, elem := range v {
+			if err := addValues(values, fieldPath(key, nestedKey), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		for _, elem := range v {
+			switch elem.(type) {
+			case map[string]interface{}, []interface{}:
+				return fmt.Errorf("EncodeQuery: %s holds a list of lists or objects, which a query string cannot hold", key)
+			}
+			if err := addValues(values, key, elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	values.Add(key, textValue(val))
+	return nil
+}
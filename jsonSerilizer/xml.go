@@ -0,0 +1,315 @@
+package jsonserilizer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// XML reads and writes XML documents, such as SOAP payloads. MarshalWith and UnmarshalWith read the
+// xml struct tags with it: `xml:"name"` names the element, `xml:"id,attr"` writes the field as an
+// attribute, `xml:",chardata"` as the text of its element, and an XMLName xml.Name field names the
+// root element, which defaults to the struct type name. Elements are written in struct field order.
+var XML Codec = xmlCodec{}
+
+// Result keys used by the XML codec. Attributes are stored under their name prefixed with @ and the
+// text of an element with attributes or children under #text, so a Result written by hand ex:
+// {"@id": "7", "#text": "hi"} is written as <root id="7">hi</root>.
+const (
+	xmlAttrPrefix = "@"
+	xmlTextKey    = "#text"
+	// xmlNameKey holds the name of the root element
+	xmlNameKey = "#name"
+	// xmlOrderKey holds the keys of a struct in field order
+	xmlOrderKey = "#order"
+	// xmlDefaultRoot names the root element of a Result without xmlNameKey
+	xmlDefaultRoot = "root"
+)
+
+// xmlMaxDepth bounds the nesting of decoded elements
+const xmlMaxDepth = 10000
+
+// xmlTag is the struct tag read by the XML codec ex: `xml:"id,attr"`
+const xmlTag = "xml"
+
+var xmlNameType = reflect.TypeOf(xml.Name{})
+
+type xmlCodec struct{}
+
+// options makes MarshalWith and UnmarshalWith read the xml tags. Every XML value is text, so
+// numbers and booleans are read like WithStringCoercion.
+func (xmlCodec) options() []Option {
+	return []Option{func(o *options) {
+		o.xml = true
+		o.stringCoercion = true
+	}}
+}
+
+func (xmlCodec) Encode(r Result) ([]byte, error) {
+	root, _ := r[xmlNameKey].(string)
+	if root == "" {
+		root = xmlDefaultRoot
+	}
+	var out bytes.Buffer
+	out.WriteString(xml.Header)
+	if err := writeXMLElement(&out, root, plainValue(r)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (xmlCodec) Decode(data []byte) (Result, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return Result{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		val, err := readXMLElement(dec, start, 0)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := val.(map[string]interface{})
+		if !ok {
+			// a root element holding only text
+			r = map[string]interface{}{}
+			if text := val.(string); text != "" {
+				r[xmlTextKey] = text
+			}
+		}
+		r[xmlNameKey] = start.Name.Local
+		return r, nil
+	}
+}
+
+// writeXMLElement writes val as an element called name. Lists repeat the element, maps become
+// attributes and children, and nil values are left out.
+func writeXMLElement(out *bytes.Buffer, name string, val interface{}) error {
+	if !isXMLName(name) {
+		return fmt.Errorf("xml: %q is not a valid element name", name)
+	}
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, elem := range v {
+			if err := writeXMLElement(out, name, elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		out.WriteString("<" + name)
+		var children []string
+		for _, key := range xmlKeys(v) {
+			attr, isAttr := strings.CutPrefix(key, xmlAttrPrefix)
+			if !isAttr {
+				children = append(children, key)
+				continue
+			}
+			if v[key] == nil {
+				continue
+			}
+			if !isXMLName(attr) {
+				return fmt.Errorf("xml: %q is not a valid attribute name", attr)
+			}
+			out.WriteString(" " + attr + `="`)
+			xml.EscapeText(out, []byte(xmlText(v[key])))
+			out.WriteString(`"`)
+		}
+		out.WriteString(">")
+		if text, ok := v[xmlTextKey]; ok && text != nil {
+			xml.EscapeText(out, []byte(xmlText(text)))
+		}
+		for _, key := range children {
+			if err := writeXMLElement(out, key, v[key]); err != nil {
+				return err
+			}
+		}
+		out.WriteString("</" + name + ">")
+		return nil
+	}
+	out.WriteString("<" + name + ">")
+	xml.EscapeText(out, []byte(xmlText(val)))
+	out.WriteString("</" + name + ">")
+	return nil
+}
+
+// xmlKeys returns the attribute and child keys of m, in the field order recorded by Serialize then
+// sorted for the keys of hand written maps
+func xmlKeys(m map[string]interface{}) []string {
+	var keys []string
+	seen := map[string]bool{xmlTextKey: true, xmlNameKey: true, xmlOrderKey: true}
+	order, _ := m[xmlOrderKey].([]string)
+	for _, key := range order {
+		if _, ok := m[key]; ok && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
+		}
+	}
+	var rest []string
+	for key := range m {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// xmlText returns the text of a scalar ex: 42, true or 1m30s for a time.Duration
+func xmlText(val interface{}) string {
+	if s, ok := val.(string); ok {
+		return s
+	}
+	return fmt.Sprint(val)
+}
+
+// isXMLName reports whether name can be written as an element or attribute name, prefixes
+// such as soap:Envelope included
+func isXMLName(name string) bool {
+	for i, c := range name {
+		if !(unicode.IsLetter(c) || c == '_' || c == ':' || i > 0 && (unicode.IsDigit(c) || c == '-' || c == '.')) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// readXMLElement reads the content of start up to its end element. An element with neither
+// attributes nor children is returned as its text, others as a map. Repeated children become a list.
+// Names are read without their namespace prefix, and namespace declarations are dropped.
+func readXMLElement(dec *xml.Decoder, start xml.StartElement, depth int) (interface{}, error) {
+	if depth == xmlMaxDepth {
+		return nil, fmt.Errorf("xml: elements nested deeper than %d levels", xmlMaxDepth)
+	}
+	m := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		m[xmlAttrPrefix+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				err = errors.New("xml: unexpected end of document")
+			}
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := readXMLElement(dec, t, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			name := t.Name.Local
+			switch existing := m[name].(type) {
+			case nil:
+				m[name] = child
+			case []interface{}:
+				m[name] = append(existing, child)
+			default:
+				m[name] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(m) == 0 {
+				return text.String(), nil
+			}
+			if trimmed := strings.TrimSpace(text.String()); trimmed != "" {
+				m[xmlTextKey] = trimmed
+			}
+			return m, nil
+		}
+	}
+}
+
+// xmlFieldInfo applies the xml tag of field to info, the json tag still applies to fields
+// without one. The XMLName field is skipped, it names the element instead, see xmlElementName.
+func xmlFieldInfo(field reflect.StructField, info fieldInfo) fieldInfo {
+	if field.Name == "XMLName" && field.Type == xmlNameType {
+		return fieldInfo{skip: true}
+	}
+	tag, ok := field.Tag.Lookup(xmlTag)
+	if !ok {
+		return info
+	}
+	if tag == "-" {
+		return fieldInfo{skip: true}
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name != "" {
+		info.key, info.tagged = name, true
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "attr":
+			info.key = xmlAttrPrefix + info.key
+		case "chardata":
+			info.key = xmlTextKey
+		case "omitempty":
+			info.omitEmpty = true
+		}
+	}
+	return info
+}
+
+// annotateXML records the element name and the field order of a struct serialized for XML
+func (o options) annotateXML(result Result, rVal reflect.Value) {
+	result[xmlNameKey] = xmlElementName(rVal)
+	result[xmlOrderKey] = o.fieldOrder(rVal.Type(), result, nil)
+}
+
+// xmlElementName returns the element name of a struct: the value or the tag of its XMLName
+// field, or else the type name without the type arguments of generic types ex: Page for
+// Page[main.User], brackets being invalid in XML names
+func xmlElementName(rVal reflect.Value) string {
+	if field, ok := rVal.Type().FieldByName("XMLName"); ok && field.Type == xmlNameType {
+		if local := rVal.FieldByIndex(field.Index).Interface().(xml.Name).Local; local != "" {
+			return local
+		}
+		if name, _, _ := strings.Cut(field.Tag.Get(xmlTag), ","); name != "" {
+			return name
+		}
+	}
+	name, _, _ := strings.Cut(rVal.Type().Name(), "[")
+	return name
+}
+
+// xmlValue adapts a value decoded from XML to field: a single element is a list of one for slices,
+// and the text of an element fills the chardata field of a struct
+func (d *decoder) xmlValue(field reflect.Value, val interface{}) interface{} {
+	val = singleElement(field, val)
+	text, ok := val.(string)
+	if !ok || field.Kind() != reflect.Struct || field.Type() == timeType {
+		return val
+	}
+	if _, ok := implementer(field, resultUnmarshalerType); ok {
+		return val
+	}
+	if _, ok := implementer(field, unmarshalerType); ok {
+		return val
+	}
+	if text == "" {
+		return map[string]interface{}{}
+	}
+	return map[string]interface{}{xmlTextKey: text}
+}
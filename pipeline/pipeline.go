@@ -0,0 +1,269 @@
+// Package pipeline composes deserialization, sanitization and validation of a request body into one
+// fluent flow, with a single report of what went wrong at every step:
+//
+//	var dto CreateUser
+//	if err := pipeline.Bind(body).Into(&dto).Transform().Validate(v).Result(); err != nil {
+//		...
+//	}
+//
+// Each step runs only when the previous ones could process the input: a body that is not JSON is not
+// validated, while a field with a value of the wrong type does not prevent the others from being
+// checked.
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+	jsonserilizer "github.com/khaledibrahim1015/goFluentValidation.git/jsonSerilizer"
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+// Rules and codes of the failures found while binding, reported alongside the validation errors
+const (
+	// RuleType is the rule of a value that could not be stored in its field ex: "abc" for an int
+	RuleType = "type"
+	// RuleUnknownKey is the rule of a key no field matches, reported WithStrict
+	RuleUnknownKey = "unknown_key"
+	// RuleRequiredKey is the rule of a missing key tagged required, reported WithRequiredKeys
+	RuleRequiredKey = "required_key"
+
+	CodeInvalidType = "BIND_INVALID_TYPE"
+	CodeUnknownKey  = "BIND_UNKNOWN_KEY"
+	CodeMissingKey  = "BIND_MISSING_KEY"
+)
+
+// TransformFunc rewrites the struct out points to between binding and validation ex: to normalize
+// an email address. An error stops the pipeline.
+type TransformFunc func(out interface{}) error
+
+// Pipeline is a request body on its way into a struct, see Bind
+type Pipeline struct {
+	data []byte
+	opts []jsonserilizer.Option
+	out  interface{}
+	// bound is set once Into populated out, the later steps need a struct
+	bound  bool
+	report Report
+}
+
+// Report is the error of Result: the failures of every step in step order
+type Report struct {
+	// Err is set when the input could not be processed at all: a body that is not a JSON object, a
+	// target that is not a pointer to a struct, a failed transform, or an *InvalidRuleError
+	Err error
+	// Fields are the fields that could not be bound, then the rules the struct failed
+	Fields validator.ValidationErrors
+	// bindErr and validateErr are the errors of the steps, for errors.As
+	bindErr, validateErr error
+}
+
+func (r *Report) Error() string {
+	var msgs []string
+	if r.Err != nil {
+		msgs = append(msgs, r.Err.Error())
+	}
+	if len(r.Fields) > 0 {
+		msgs = append(msgs, r.Fields.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of the steps, so errors.As finds the jsonserilizer.FieldTypeErrors and
+// the validator.ValidationErrors as returned by each package
+func (r *Report) Unwrap() []error {
+	var errs []error
+	for _, err := range []error{r.Err, r.bindErr, r.validateErr} {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Bind starts a pipeline for a JSON body, opts are handed to the serializer ex: WithStrict
+func Bind(data []byte, opts ...jsonserilizer.Option) *Pipeline {
+	return &Pipeline{data: data, opts: opts}
+}
+
+// Into populates the struct out points to from the body like jsonserilizer.Unmarshal. Values of the
+// wrong type, and the unknown or missing keys of the options, are reported as field failures.
+func (p *Pipeline) Into(out interface{}) *Pipeline {
+	if p.report.Err != nil {
+		return p
+	}
+	rVal := reflect.ValueOf(out)
+	if rVal.Kind() != reflect.Pointer || rVal.IsNil() || rVal.Elem().Kind() != reflect.Struct {
+		p.report.Err = fmt.Errorf("pipeline: Into requires a non nil pointer to a struct, got %T", out)
+		return p
+	}
+	p.out = out
+	err := jsonserilizer.Unmarshal(p.data, out, p.opts...)
+	if err == nil {
+		p.bound = true
+		return p
+	}
+	fields, ok := bindFailures(err)
+	if !ok {
+		p.report.Err = err
+		return p
+	}
+	p.bound = true
+	p.report.bindErr = err
+	p.report.Fields = append(p.report.Fields, fields...)
+	return p
+}
+
+// Transform runs transforms, in order, on the bound struct. Without transforms the strings of the
+// struct are trimmed, see TrimStrings.
+func (p *Pipeline) Transform(transforms ...TransformFunc) *Pipeline {
+	if !p.ready() {
+		return p
+	}
+	if len(transforms) == 0 {
+		transforms = []TransformFunc{TrimStrings}
+	}
+	for _, transform := range transforms {
+		if err := transform(p.out); err != nil {
+			p.report.Err = err
+			return p
+		}
+	}
+	return p
+}
+
+// Validate validates the bound struct with v, or validator.Default() when v is nil, even when some
+// fields could not be bound
+func (p *Pipeline) Validate(v *validator.Validator, opts ...validator.Option) *Pipeline {
+	if !p.ready() {
+		return p
+	}
+	if v == nil {
+		v = validator.Default()
+	}
+	err := v.Validate(p.out, opts...)
+	if err == nil {
+		return p
+	}
+	p.report.validateErr = err
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		p.report.Err = err
+		return p
+	}
+	p.report.Fields = append(p.report.Fields, errs...)
+	return p
+}
+
+// Result returns nil when every step passed, or else a *Report
+func (p *Pipeline) Result() error {
+	if p.report.Err == nil && len(p.report.Fields) == 0 {
+		return nil
+	}
+	report := p.report
+	return &report
+}
+
+// ready reports whether a struct was bound and no step failed
+func (p *Pipeline) ready() bool {
+	if p.report.Err == nil && !p.bound {
+		p.report.Err = errors.New("pipeline: Into must be called before the other steps")
+	}
+	return p.report.Err == nil
+}
+
+// bindFailures converts the field errors of Deserialize into validation errors, ok is false for
+// other errors such as invalid JSON
+func bindFailures(err error) (fields validator.ValidationErrors, ok bool) {
+	errs := []error{err}
+	if joined, isJoined := err.(interface{ Unwrap() []error }); isJoined {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		var (
+			typeErrs   jsonserilizer.FieldTypeErrors
+			unknownErr *jsonserilizer.UnknownKeysError
+			missingErr *jsonserilizer.MissingKeysError
+		)
+		switch {
+		case errors.As(err, &typeErrs):
+			for _, typeErr := range typeErrs {
+				msg := fmt.Sprintf("expected %s, got %s", typeErr.Expected, typeErr.Got)
+				if typeErr.Reason != "" {
+					msg += ": " + typeErr.Reason
+				}
+				fields = append(fields, validator.ValidationError{Field: typeErr.Field, Message: msg, Rule: RuleType, Code: CodeInvalidType})
+			}
+		case errors.As(err, &unknownErr):
+			for _, key := range unknownErr.Keys {
+				fields = append(fields, validator.ValidationError{Field: key, Message: "unknown key", Rule: RuleUnknownKey, Code: CodeUnknownKey})
+			}
+		case errors.As(err, &missingErr):
+			for _, key := range missingErr.Keys {
+				fields = append(fields, validator.ValidationError{Field: key, Message: "key is required", Rule: RuleRequiredKey, Code: CodeMissingKey})
+			}
+		default:
+			return nil, false
+		}
+	}
+	return fields, true
+}
+
+// TrimStrings removes the leading and trailing white space of every string out holds: its string
+// fields, those of nested and pointed to structs, and the strings of slices, arrays and maps.
+// Unexported fields are left alone.
+func TrimStrings(out interface{}) error {
+	rVal := reflect.ValueOf(out)
+	if rVal.Kind() != reflect.Pointer || rVal.IsNil() {
+		return fmt.Errorf("pipeline: TrimStrings requires a non nil pointer, got %T", out)
+	}
+	trimValue(rVal.Elem(), make(map[uintptr]bool))
+	return nil
+}
+
+// trimValue trims the strings of val, visited holds the pointers walked so cycles terminate
+func trimValue(val reflect.Value, visited map[uintptr]bool) {
+	switch val.Kind() {
+	case reflect.String:
+		if val.CanSet() {
+			val.SetString(strings.TrimSpace(val.String()))
+		}
+	case reflect.Pointer:
+		if val.IsNil() || visited[val.Pointer()] {
+			return
+		}
+		visited[val.Pointer()] = true
+		trimValue(val.Elem(), visited)
+	case reflect.Interface:
+		if val.IsNil() || !val.CanSet() {
+			return
+		}
+		// the value an interface holds cannot be set in place
+		elem := reflect.New(val.Elem().Type()).Elem()
+		elem.Set(val.Elem())
+		trimValue(elem, visited)
+		val.Set(elem)
+	case reflect.Struct:
+		for _, field := range structwalk.Fields(val.Type()) {
+			if field.Walkable() {
+				trimValue(val.Field(field.Pos), visited)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			trimValue(val.Index(i), visited)
+		}
+	case reflect.Map:
+		iter := val.MapRange()
+		for iter.Next() {
+			// map values cannot be set in place
+			elem := reflect.New(val.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			trimValue(elem, visited)
+			val.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package rules evaluates validate tag rule strings against plain values, without structs, so the
+// same rules can drive form builders and dynamic pipelines:
+//
+//	if err := rules.Check(input, "required,min=3,max=10"); err != nil {
+//		...
+//	}
+package rules
+
+import (
+	"context"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/validator"
+)
+
+// Spec is one rule of a rule string ex: Name min, Param 3 for min=3
+type Spec = validator.RuleSpec
+
+// Check applies rules to value with the default Validator, so custom validators and Rule plugins
+// registered on validator.Default() are available
+func Check(value interface{}, rules string) error {
+	return validator.Default().Check(value, rules)
+}
+
+// CheckContext is Check with a context handed to Rule plugins
+func CheckContext(ctx context.Context, value interface{}, rules string) error {
+	return validator.Default().CheckContext(ctx, value, rules)
+}
+
+// CheckWith applies rules to value with v
+func CheckWith(v *validator.Validator, value interface{}, rules string) error {
+	return v.Check(value, rules)
+}
+
+// Parse splits a rule string into its rules, so form builders can mirror them client side
+// ex: render maxlength from max=10. Quoting and escapes are handled like in struct tags.
+func Parse(rules string) []Spec {
+	return validator.ParseTag(rules)
+}
@@ -0,0 +1,55 @@
+package validator
+
+import "fmt"
+
+// ItemFailure is an item of a batch that failed validation
+type ItemFailure struct {
+	// Index is the position of the item in the batch
+	Index int
+	// Err is the error returned by Validate for the item, usually ValidationErrors
+	Err error
+}
+
+// BatchReport is the outcome of ValidateAll
+type BatchReport struct {
+	// Total is the number of items validated
+	Total int
+	// Failures lists the items that failed, in batch order
+	Failures []ItemFailure
+}
+
+// ValidateAll validates every item like Validate and reports which ones failed and why,
+// so bulk imports can keep going past invalid rows
+func (v *Validator) ValidateAll(items ...interface{}) *BatchReport {
+	report := &BatchReport{Total: len(items)}
+	for i, item := range items {
+		if err := v.Validate(item); err != nil {
+			report.Failures = append(report.Failures, ItemFailure{Index: i, Err: err})
+		}
+	}
+	return report
+}
+
+// Valid reports whether every item passed
+func (r *BatchReport) Valid() bool {
+	return len(r.Failures) == 0
+}
+
+// Errors flattens the validation errors of every failed item, prefixing fields with the item index ex: [3].Email.
+// Failures that are not ValidationErrors, such as an *InvalidRuleError, are reported under the index alone.
+func (r *BatchReport) Errors() ValidationErrors {
+	var out ValidationErrors
+	for _, failure := range r.Failures {
+		prefix := fmt.Sprintf("[%d]", failure.Index)
+		errs, ok := failure.Err.(ValidationErrors)
+		if !ok {
+			out = append(out, ValidationError{Field: prefix, Message: failure.Err.Error()})
+			continue
+		}
+		for _, errVal := range errs {
+			errVal.Field = fieldPath(prefix, errVal.Field)
+			out = append(out, errVal)
+		}
+	}
+	return out
+}
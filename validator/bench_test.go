@@ -0,0 +1,48 @@
+package validator
+
+import "testing"
+
+// benchFlat only holds scalars, validateFields applies its rules without walking the fields
+type benchFlat struct {
+	ID     int64   `validate:"required,min=1"`
+	Name   string  `validate:"required,min=2,max=50"`
+	Email  string  `validate:"required,email"`
+	Age    int     `validate:"range=0:150"`
+	Score  float64 `validate:"min=0"`
+	Active bool
+	Role   string `validate:"oneof=admin user"`
+}
+
+// benchGeneral holds the fields of benchFlat and a nil pointer, which sends it down the general path
+type benchGeneral struct {
+	ID     int64   `validate:"required,min=1"`
+	Name   string  `validate:"required,min=2,max=50"`
+	Email  string  `validate:"required,email"`
+	Age    int     `validate:"range=0:150"`
+	Score  float64 `validate:"min=0"`
+	Active bool
+	Role   string `validate:"oneof=admin user"`
+	Note   *string
+}
+
+func BenchmarkValidate(b *testing.B) {
+	v := New()
+	flat := &benchFlat{ID: 7, Name: "Ada", Email: "ada@example.com", Age: 36, Score: 9.5, Active: true, Role: "admin"}
+	general := &benchGeneral{ID: 7, Name: "Ada", Email: "ada@example.com", Age: 36, Score: 9.5, Active: true, Role: "admin"}
+	b.Run("flat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := v.Validate(flat); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("general", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := v.Validate(general); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
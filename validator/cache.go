@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// CacheKeyFunc returns the hash identifying the content of an immutable value, ok is false for values
+// that must not be cached
+type CacheKeyFunc func(s interface{}) (hash uint64, ok bool)
+
+// resultCache holds Validate results by value hash
+type resultCache struct {
+	mu         sync.Mutex
+	key        CacheKeyFunc
+	maxEntries int
+	results    map[cacheKey]error
+}
+
+// cacheKey identifies a cached result, the same value validated with other options is validated again
+type cacheKey struct {
+	structType reflect.Type
+	hash       uint64
+	opts       string
+}
+
+// SetResultCache caches Validate results of values hashed by key, so validating the same immutable config
+// or value object again short-circuits to the stored result. The cache is emptied once it holds maxEntries
+// results, 0 means no limit. Hooks, which could change the value, do not run for cached results.
+// Configure the Validator before enabling the cache, results are not invalidated by later registrations.
+// A nil key disables the cache.
+func (v *Validator) SetResultCache(key CacheKeyFunc, maxEntries int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if key == nil {
+		v.cache = nil
+		return
+	}
+	v.cache = &resultCache{key: key, maxEntries: maxEntries, results: make(map[cacheKey]error)}
+}
+
+// cachedValidate returns the cached result for s, validating it and storing the result on a miss
+func (v *Validator) cachedValidate(ctx context.Context, s interface{}, opts callOptions) error {
+	v.mu.RLock()
+	cache := v.cache
+	v.mu.RUnlock()
+	if cache == nil || checkNil(s) != nil {
+		return v.validate(ctx, s, opts)
+	}
+
+	hash, ok := cache.key(s)
+	if !ok {
+		return v.validate(ctx, s, opts)
+	}
+	key := cacheKey{structType: reflect.TypeOf(s), hash: hash, opts: fmt.Sprintf("%q %t %q", opts.groups, opts.failFast, opts.locale)}
+
+	cache.mu.Lock()
+	err, hit := cache.results[key]
+	cache.mu.Unlock()
+	if hit {
+		return copyResult(err)
+	}
+
+	err = v.validate(ctx, s, opts)
+	cache.mu.Lock()
+	if cache.maxEntries > 0 && len(cache.results) >= cache.maxEntries {
+		cache.results = make(map[cacheKey]error)
+	}
+	cache.results[key] = copyResult(err)
+	cache.mu.Unlock()
+	return err
+}
+
+// copyResult copies ValidationErrors so callers cannot change a cached result
+func copyResult(err error) error {
+	if errs, ok := err.(ValidationErrors); ok {
+		return append(ValidationErrors(nil), errs...)
+	}
+	return err
+}
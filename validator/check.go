@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// Check applies the rules of a validate tag to a single value ex: v.Check(name, "required,min=3,max=10"),
+// without a struct around it. Failures are returned as ValidationErrors without a field name. Rules reading
+// other fields, such as required_if, have no struct to read and report an *InvalidRuleError.
+func (v *Validator) Check(value interface{}, tagVal string, opts ...Option) error {
+	return v.CheckContext(context.Background(), value, tagVal, opts...)
+}
+
+// CheckContext is Check with a context handed to Rule plugins
+func (v *Validator) CheckContext(ctx context.Context, value interface{}, tagVal string, opts ...Option) error {
+	if v == nil {
+		return ErrNilValidator
+	}
+	run := &validation{Validator: v, ctx: ctx, opts: v.newCallOptions(opts), errors: ValidationErrors{}, visited: make(map[uintptr]bool)}
+
+	val := reflect.ValueOf(value)
+	if !val.IsValid() {
+		// nil is checked as an empty interface so required and omitempty see a zero value
+		val = reflect.ValueOf(&value).Elem()
+	}
+
+	var rules []string
+	if tagVal != "" {
+		rules = splitRules(tagVal)
+	}
+	if err := run.validateValue(val, reflect.Value{}, "", rules, -1); err != nil && err != errFailFast {
+		return err
+	}
+	if len(run.errors) > 0 {
+		return run.errors
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// cleanTag names the struct tag combining sanitizers and rules, the sanitizers before the first |
+// and the rules after it ex: `clean:"trim,lower|required,email"`
+const cleanTag = "clean"
+
+// Built-in sanitizers of the clean tag
+const (
+	trimSanitizer  = "trim"
+	lowerSanitizer = "lower"
+	upperSanitizer = "upper"
+)
+
+// SanitizerFunc rewrites a string before the rules of its field are applied ex: to strip punctuation
+// from a phone number
+type SanitizerFunc func(s string) string
+
+// RegisterSanitizer registers fn under name for the clean tag, the built-in trim, lower and upper
+// cannot be replaced
+func (v *Validator) RegisterSanitizer(name string, fn SanitizerFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.sanitizers[name] = fn
+}
+
+// parseCleanTag splits a clean tag into its sanitizers and its rules
+func parseCleanTag(tag string) (sanitizers, rules []string) {
+	sanitizersPart, rulesPart, _ := strings.Cut(tag, "|")
+	for _, name := range strings.Split(sanitizersPart, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			sanitizers = append(sanitizers, name)
+		}
+	}
+	if rulesPart = strings.TrimSpace(rulesPart); rulesPart != "" {
+		rules = splitRules(rulesPart)
+	}
+	return sanitizers, rules
+}
+
+// sanitizer returns the function of a sanitizer name, ok is false when none is known
+func (v *Validator) sanitizer(name string) (SanitizerFunc, bool) {
+	switch name {
+	case trimSanitizer:
+		return strings.TrimSpace, true
+	case lowerSanitizer:
+		return strings.ToLower, true
+	case upperSanitizer:
+		return strings.ToUpper, true
+	}
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	fn, ok := v.sanitizers[name]
+	return fn, ok
+}
+
+// sanitize applies the sanitizers, in order, to a string field, a non nil pointer to one or the
+// elements of a string slice, in place
+func (v *Validator) sanitize(field reflect.Value, fieldName string, sanitizers []string) error {
+	for _, name := range sanitizers {
+		fn, ok := v.sanitizer(name)
+		if !ok {
+			return &InvalidRuleError{Field: fieldName, Rule: name, Reason: "unknown sanitizer"}
+		}
+		if err := sanitizeValue(field, fieldName, name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sanitizeValue rewrites the strings val holds with fn
+func sanitizeValue(val reflect.Value, fieldName, name string, fn SanitizerFunc) error {
+	switch val.Kind() {
+	case reflect.String:
+		// the fields of structs held by maps are copies
+		if !val.CanSet() {
+			return &InvalidRuleError{Field: fieldName, Rule: name, Reason: "the value cannot be rewritten in place"}
+		}
+		val.SetString(fn(val.String()))
+		return nil
+	case reflect.Pointer:
+		if val.IsNil() {
+			return nil
+		}
+		if val.Elem().Kind() == reflect.String {
+			return sanitizeValue(val.Elem(), fieldName, name, fn)
+		}
+	case reflect.Slice:
+		if val.Type().Elem().Kind() == reflect.String {
+			for i := 0; i < val.Len(); i++ {
+				val.Index(i).SetString(fn(val.Index(i).String()))
+			}
+			return nil
+		}
+	}
+	return &InvalidRuleError{Field: fieldName, Rule: name, Reason: fmt.Sprintf("sanitizers apply to strings, not %s", val.Type())}
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Error codes are stable, machine readable identifiers of rule failures, so clients can map them
+// to their own copy whatever the server messages. Custom validators and rules report VAL_ followed
+// by their tag in upper case ex: VAL_VALID_USERNAME, negated rules VAL_NOT_ followed by the code of
+// the rule ex: VAL_NOT_ONE_OF.
+const (
+	CodeRequired      = "VAL_REQUIRED"
+	CodeNotBlank      = "VAL_NOT_BLANK"
+	CodeMustBeDefault = "VAL_MUST_BE_DEFAULT"
+	CodeRequiredIf    = "VAL_REQUIRED_IF"
+	CodeRequiredIfAny = "VAL_REQUIRED_IF_ANY"
+	CodeMinLength     = "VAL_MIN_LENGTH"
+	CodeMinValue      = "VAL_MIN_VALUE"
+	CodeMinItems      = "VAL_MIN_ITEMS"
+	CodeMaxLength     = "VAL_MAX_LENGTH"
+	CodeMaxValue      = "VAL_MAX_VALUE"
+	CodeMaxItems      = "VAL_MAX_ITEMS"
+	CodeRangeLength   = "VAL_RANGE_LENGTH"
+	CodeRangeValue    = "VAL_RANGE_VALUE"
+	CodeRangeItems    = "VAL_RANGE_ITEMS"
+	CodeDigits        = "VAL_DIGITS"
+	CodeDecimal       = "VAL_DECIMAL"
+	CodeEmail         = "VAL_EMAIL"
+	CodeRegex         = "VAL_PATTERN"
+	CodeOneOf         = "VAL_ONE_OF"
+	CodeContains      = "VAL_CONTAINS"
+	CodeE164          = "VAL_E164"
+	CodeJSON          = "VAL_JSON"
+	CodeNotBeforeNow  = "VAL_NOT_BEFORE_NOW"
+	CodeNotAfterNow   = "VAL_NOT_AFTER_NOW"
+	CodeWithin        = "VAL_WITHIN"
+	CodeUniqueWith    = "VAL_UNIQUE_WITH"
+	CodeNoAlternative = "VAL_NO_ALTERNATIVE"
+	codePrefix        = "VAL_"
+	negatedCodePrefix = "VAL_NOT_"
+)
+
+// ruleCodes maps the built-in rules whose code does not depend on the field kind
+var ruleCodes = map[string]string{
+	required: CodeRequired, notBlank: CodeNotBlank, isDefault: CodeMustBeDefault,
+	requiredIf: CodeRequiredIf, requiredIfAny: CodeRequiredIfAny, uniqueWith: CodeUniqueWith,
+	digits: CodeDigits, decimal: CodeDecimal, email: CodeEmail, regex: CodeRegex, oneOf: CodeOneOf,
+	contains: CodeContains, e164: CodeE164, jsonRule: CodeJSON,
+	gteNow: CodeNotBeforeNow, lteNow: CodeNotAfterNow, within: CodeWithin,
+}
+
+// sizedRuleCodes maps min, max and range to their codes for lengths, values and item counts
+var sizedRuleCodes = map[string][3]string{
+	min:       {CodeMinLength, CodeMinValue, CodeMinItems},
+	max:       {CodeMaxLength, CodeMaxValue, CodeMaxItems},
+	rangeRule: {CodeRangeLength, CodeRangeValue, CodeRangeItems},
+}
+
+// errorCode returns the code reported when rule, as named in ValidationError.Rule, fails on the field
+func errorCode(rule string, currentFieldVal reflect.Value) string {
+	if strings.Contains(rule, orSeparator) {
+		return CodeNoAlternative
+	}
+	if inner, ok := strings.CutPrefix(rule, negation); ok {
+		return negatedCodePrefix + strings.TrimPrefix(errorCode(inner, currentFieldVal), codePrefix)
+	}
+
+	if code, ok := ruleCodes[rule]; ok {
+		return code
+	}
+	if codes, ok := sizedRuleCodes[rule]; ok {
+		switch {
+		case currentFieldVal.Kind() == reflect.String || isBytes(currentFieldVal):
+			return codes[0]
+		case currentFieldVal.Kind() == reflect.Slice || currentFieldVal.Kind() == reflect.Array || currentFieldVal.Kind() == reflect.Map:
+			return codes[2]
+		}
+		return codes[1]
+	}
+
+	return codePrefix + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r - 'a' + 'A'
+		}
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, rule)
+}
@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+)
+
+// Coverage records the rules of struct tags, and of validate_method tags, applied by the
+// Validators it is installed on, so test suites can find rules they never exercise and fields their
+// structs never validate. See SetCoverage.
+type Coverage struct {
+	mu    sync.Mutex
+	rules map[coverageKey]*RuleCoverage
+	// types holds the struct types validated, their fields are checked by UnvalidatedFields
+	types map[reflect.Type]bool
+}
+
+// RuleCoverage counts the outcomes of a rule of a field
+type RuleCoverage struct {
+	// Type is the struct type holding the field ex: main.User
+	Type string
+	// Field is the Go name of the field, or of the method of a validate_method tag
+	Field string
+	// Rule is the rule as written in the tag ex: min=2
+	Rule           string
+	Passed, Failed int
+}
+
+// coverageKey identifies a rule of a field of a struct type
+type coverageKey struct {
+	typ         reflect.Type
+	field, rule string
+}
+
+// coveredField is the field whose rules are being applied, see validation.cover
+type coveredField struct {
+	typ  reflect.Type
+	name string
+}
+
+// NewCoverage returns an empty Coverage
+func NewCoverage() *Coverage {
+	return &Coverage{rules: make(map[coverageKey]*RuleCoverage), types: make(map[reflect.Type]bool)}
+}
+
+// SetCoverage records the rules v applies into coverage, clones included, nil stops recording.
+// A Coverage can be shared by several Validators.
+func (v *Validator) SetCoverage(coverage *Coverage) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.coverage = coverage
+}
+
+// coverType records that the struct type typ was validated
+func (c *Coverage) coverType(typ reflect.Type) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.types[typ] = true
+}
+
+// coverRule records an outcome of rule on field
+func (c *Coverage) coverRule(field coveredField, rule string, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := coverageKey{typ: field.typ, field: field.name, rule: rule}
+	covered, ok := c.rules[key]
+	if !ok {
+		covered = &RuleCoverage{Type: field.typ.String(), Field: field.name, Rule: rule}
+		c.rules[key] = covered
+	}
+	if failed {
+		covered.Failed++
+	} else {
+		covered.Passed++
+	}
+}
+
+// Rules returns the rules applied so far with their outcomes, ordered by type, field and rule
+func (c *Coverage) Rules() []RuleCoverage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rules := make([]RuleCoverage, 0, len(c.rules))
+	for _, covered := range c.rules {
+		rules = append(rules, *covered)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].Type != rules[j].Type {
+			return rules[i].Type < rules[j].Type
+		}
+		if rules[i].Field != rules[j].Field {
+			return rules[i].Field < rules[j].Field
+		}
+		return rules[i].Rule < rules[j].Rule
+	})
+	return rules
+}
+
+// UnappliedRules returns the rules of the validate tags of the struct types validated so far that
+// were never applied ex: rules of a validation group no test uses, as Type.Field: rule
+func (c *Coverage) UnappliedRules() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var unapplied []string
+	for typ := range c.types {
+		for _, field := range structPlanOf(typ).fields {
+			if field.tag == skipField || field.unvalidatable != "" {
+				continue
+			}
+			for _, rule := range field.rules {
+				rule = strings.Trim(rule, " ")
+				if isMarkerRule(rule) {
+					continue
+				}
+				if _, ok := c.rules[coverageKey{typ: typ, field: field.Name, rule: rule}]; !ok {
+					unapplied = append(unapplied, fmt.Sprintf("%s.%s: %s", typ, field.Name, rule))
+				}
+			}
+		}
+	}
+	sort.Strings(unapplied)
+	return unapplied
+}
+
+// UnvalidatedFields returns the fields of the struct types validated so far that no rule checks, as
+// Type.Field. Fields tagged validate:"-", fields that are never validated such as unexported ones,
+// and fields holding structs, whose own fields are reported, are left out.
+func (c *Coverage) UnvalidatedFields() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	applied := make(map[coveredField]bool, len(c.rules))
+	for key := range c.rules {
+		applied[coveredField{typ: key.typ, name: key.field}] = true
+	}
+	var unvalidated []string
+	for typ := range c.types {
+		for _, field := range structPlanOf(typ).fields {
+			if field.tag == skipField || field.unvalidatable != "" || holdsStructs(field.Type) {
+				continue
+			}
+			if hasRules(field.rules) || applied[coveredField{typ: typ, name: field.Name}] {
+				continue
+			}
+			unvalidated = append(unvalidated, fmt.Sprintf("%s.%s", typ, field.Name))
+		}
+	}
+	sort.Strings(unvalidated)
+	return unvalidated
+}
+
+// Report renders the coverage for test logs: the rules applied with their outcomes, then the rules
+// never applied and the fields never validated
+func (c *Coverage) Report() string {
+	var sb strings.Builder
+	for _, covered := range c.Rules() {
+		fmt.Fprintf(&sb, "%s.%s: %s passed %d failed %d", covered.Type, covered.Field, covered.Rule, covered.Passed, covered.Failed)
+		if covered.Failed == 0 {
+			sb.WriteString(" (never failed)")
+		}
+		sb.WriteByte('\n')
+	}
+	for _, rule := range c.UnappliedRules() {
+		fmt.Fprintf(&sb, "%s never applied\n", rule)
+	}
+	for _, field := range c.UnvalidatedFields() {
+		fmt.Fprintf(&sb, "%s has no rules\n", field)
+	}
+	return sb.String()
+}
+
+// isMarkerRule reports whether rule only steers the walk ex: omitempty or dive, it is not applied
+func isMarkerRule(rule string) bool {
+	name, _ := parseRule(rule)
+	switch name {
+	case omitEmpty, dive, structOnly, skipUnless:
+		return true
+	}
+	return false
+}
+
+// hasRules reports whether rules hold a rule that is applied, not only markers
+func hasRules(rules []string) bool {
+	for _, rule := range rules {
+		if !isMarkerRule(strings.Trim(rule, " ")) {
+			return true
+		}
+	}
+	return false
+}
+
+// holdsStructs reports whether values of typ are walked for nested fields: structs with exported
+// fields, and pointers, slices, arrays and maps of them. A time.Time has none to walk.
+func holdsStructs(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		typ = typ.Elem()
+	}
+	if !structwalk.IsStruct(typ) {
+		return false
+	}
+	for _, field := range structwalk.Fields(structwalk.StructType(typ)) {
+		if field.Walkable() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	defaultValidator     *Validator
+	defaultValidatorOnce sync.Once
+)
+
+// Default returns the Validator behind the package level functions, created on first use.
+// Use it to configure the default instance ex: validator.RegisterTypedValidator(validator.Default(), ...)
+func Default() *Validator {
+	defaultValidatorOnce.Do(func() {
+		defaultValidator = New()
+	})
+	return defaultValidator
+}
+
+// Validate validates s with the default Validator
+func Validate(s interface{}, opts ...Option) error {
+	return Default().Validate(s, opts...)
+}
+
+// ValidateContext validates s with the default Validator
+func ValidateContext(ctx context.Context, s interface{}, opts ...Option) error {
+	return Default().ValidateContext(ctx, s, opts...)
+}
+
+// ValidateAll validates every item with the default Validator
+func ValidateAll(items ...interface{}) *BatchReport {
+	return Default().ValidateAll(items...)
+}
+
+// Check applies the rules of a validate tag to a single value with the default Validator
+func Check(value interface{}, tagVal string, opts ...Option) error {
+	return Default().Check(value, tagVal, opts...)
+}
+
+// RegisterCustomValidator registers a custom validation function on the default Validator
+func RegisterCustomValidator(tagVal string, fn CustomValidatorFunc) {
+	Default().RegisterCustomValidator(tagVal, fn)
+}
+
+// RegisterCustomValidatorWithParent registers a parent aware custom validation function on the default Validator
+func RegisterCustomValidatorWithParent(tagVal string, fn CustomValidatorWithParentFunc) {
+	Default().RegisterCustomValidatorWithParent(tagVal, fn)
+}
+
+// RegisterRule registers a Rule on the default Validator
+func RegisterRule(r Rule) {
+	Default().RegisterRule(r)
+}
@@ -0,0 +1,275 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+)
+
+// DocFormat selects the output of DocumentRules
+type DocFormat int
+
+const (
+	// MarkdownFormat renders a GitHub flavored Markdown table
+	MarkdownFormat DocFormat = iota
+)
+
+// RuleDoc describes a rule of a field, see DescribeRules
+type RuleDoc struct {
+	// Field is the path of the field as reported in errors, elements and map values written []
+	// ex: Items[].SKU
+	Field string
+	// Type is the Go type of the field ex: []string
+	Type string
+	// Rule is the name of the rule ex: min, or the whole rule for alternatives ex: email|e164
+	Rule string
+	// Param is the parameter of the rule ex: 2 for min=2
+	Param string
+	// Code is the error code reported when the rule fails ex: VAL_MIN_LENGTH, empty for the rules
+	// that never fail ex: omitempty
+	Code string
+	// Message is the message reported when the rule fails, in the locale set with SetLocale. It
+	// explains the rule when the message depends on the value ex: for custom validators.
+	Message string
+}
+
+// DocumentRules renders the rules of the struct type of s, a struct or a pointer to one which may
+// be nil ex: (*User)(nil), as a table of fields, rules, parameters and messages to paste into API
+// documentation
+func (v *Validator) DocumentRules(s interface{}, format DocFormat) (string, error) {
+	docs, err := v.DescribeRules(s)
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case MarkdownFormat:
+		return markdownRules(docs), nil
+	}
+	return "", fmt.Errorf("validator: unknown DocFormat %d", format)
+}
+
+// DescribeRules returns the rules of the struct type of s in the order Validate applies them:
+// the rules of validate and clean tags, of validate_method tags and those inherited through
+// RegisterInterfaceRules, with the fields of nested structs and dived elements
+func (v *Validator) DescribeRules(s interface{}) ([]RuleDoc, error) {
+	typ := reflect.TypeOf(s)
+	if typ == nil || !structwalk.IsStruct(typ) {
+		return nil, fmt.Errorf("validator: DescribeRules requires a struct or a pointer to a struct, got %T", s)
+	}
+	d := &ruleDocs{Validator: v, opts: v.newCallOptions(nil)}
+	if err := d.structDocs(structwalk.StructType(typ), ""); err != nil {
+		return nil, err
+	}
+	return d.docs, nil
+}
+
+// ruleDocs collects the RuleDoc of a struct type, parents holds the struct types being described
+// so a type holding itself ends the nesting
+type ruleDocs struct {
+	*Validator
+	opts    callOptions
+	docs    []RuleDoc
+	parents []reflect.Type
+}
+
+func (d *ruleDocs) structDocs(typ reflect.Type, prefix string) error {
+	for _, parent := range d.parents {
+		if parent == typ {
+			return nil
+		}
+	}
+	d.parents = append(d.parents, typ)
+	defer func() { d.parents = d.parents[:len(d.parents)-1] }()
+
+	inherited, err := d.inheritedRules(reflect.New(typ).Elem(), prefix)
+	if err != nil {
+		return err
+	}
+	plan := structPlanOf(typ)
+	for _, field := range plan.fields {
+		if field.tag == skipField || field.unvalidatable != "" {
+			continue
+		}
+		fieldName := fieldPath(prefix, d.opts.fieldName(field.Name))
+		for _, sanitizer := range field.sanitizers {
+			d.docs = append(d.docs, RuleDoc{Field: fieldName, Type: field.Type.String(), Rule: sanitizer, Message: "rewrites the value before the rules"})
+		}
+		rules := append([]string(nil), inherited[field.Name]...)
+		if d.hasFieldValidator(typ, field.validatorRule) {
+			rules = append(rules, field.validatorRule)
+		}
+		rules = append(rules, field.rules...)
+		if err := d.valueDocs(field.Type, fieldName, rules); err != nil {
+			return err
+		}
+	}
+	for _, method := range plan.methods {
+		fieldName := fieldPath(prefix, d.opts.fieldName(method.name))
+		if method.invalid != "" {
+			return &InvalidRuleError{Field: fieldName, Rule: strings.Join(method.rules, ","), Reason: method.invalid}
+		}
+		fn, _ := reflect.PointerTo(typ).MethodByName(method.name)
+		if err := d.valueDocs(fn.Type.Out(0), fieldName, method.rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// valueDocs describes the rules of a value of type typ, then those of its elements after dive and
+// the fields of the structs it holds, like validateValue walks them
+func (d *ruleDocs) valueDocs(typ reflect.Type, fieldName string, rules []string) error {
+	rules, elemRules, hasDive := splitDive(rules)
+	for _, rule := range presenceFirst(rules) {
+		d.ruleDoc(typ, fieldName, strings.Trim(rule, " "))
+	}
+	if hasDive {
+		switch typ.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return d.valueDocs(typ.Elem(), fieldName+"[]", elemRules)
+		}
+		return &InvalidRuleError{Field: fieldName, Rule: dive, Kind: typ.Kind()}
+	}
+	if hasRule(rules, structOnly) {
+		return nil
+	}
+	if typ.Kind() == reflect.Map && structwalk.IsStruct(typ.Elem()) {
+		return d.structDocs(structwalk.StructType(typ.Elem()), fieldName+"[]")
+	}
+	if structwalk.IsStruct(typ) {
+		return d.structDocs(structwalk.StructType(typ), fieldName)
+	}
+	return nil
+}
+
+func (d *ruleDocs) ruleDoc(typ reflect.Type, fieldName, rule string) {
+	name, param := parseRule(rule)
+	switch name {
+	case dive, structOnly, "":
+		return
+	}
+	// string rules measure []byte fields as text
+	measured := typ
+	if bytesAsStringRules[strings.TrimPrefix(name, negation)] && typ.Kind() == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+		measured = reflect.TypeOf("")
+	}
+	doc := RuleDoc{Field: fieldName, Type: typ.String(), Rule: name, Param: param}
+	if alternatives, isOr := d.splitAlternatives(rule); isOr {
+		var msgs []string
+		for _, alternative := range alternatives {
+			msgs = append(msgs, ruleMessage(strings.Trim(alternative, " "), measured))
+		}
+		doc.Rule, doc.Param, doc.Message = rule, "", strings.Join(msgs, " or ")
+	} else {
+		doc.Message = ruleMessage(rule, measured)
+	}
+	if name != omitEmpty && name != skipUnless {
+		doc.Code = errorCode(doc.Rule, reflect.Zero(measured))
+		if d.opts.locale != "" {
+			doc.Message = d.message(d.opts.locale, ValidationError{Field: fieldName, Message: doc.Message, Rule: name, Code: doc.Code}, param)
+		}
+	}
+	d.docs = append(d.docs, doc)
+}
+
+// ruleMessage is the message a field of type typ is reported with when rule fails, rendered from
+// the built-in templates with the parameter shown as validation shows it, or what rule checks when
+// its message depends on the value. A parameter validation cannot read leaves the rule name.
+func ruleMessage(rule string, typ reflect.Type) string {
+	if inner, ok := strings.CutPrefix(rule, negation); ok {
+		return negatedError(inner).Error()
+	}
+	name, param := parseRule(rule)
+	switch name {
+	case omitEmpty:
+		return "optional, the other rules apply when it is set"
+	case skipUnless:
+		return fmt.Sprintf("the other rules apply when %s", param)
+	}
+	code := errorCode(name, reflect.Zero(typ))
+	if _, ok := defaultMessages[code]; !ok {
+		return "reported by the custom validator"
+	}
+
+	var placeholders []string
+	switch name {
+	case min, max, digits:
+		n, err := strconv.Atoi(param)
+		if err != nil || name == digits && n < 1 {
+			return name
+		}
+		param = strconv.Itoa(n)
+	case oneOf:
+		param = listParam(param)
+	case contains:
+		param = strconv.Quote(param)
+	case rangeRule:
+		low, high, ok := rangeBounds(param)
+		if !ok {
+			return name
+		}
+		param, placeholders = "", []string{"{low}", boundParam(low), "{high}", boundParam(high)}
+	case decimal:
+		precision, scale, ok := decimalBounds(param)
+		if !ok {
+			return name
+		}
+		param, placeholders = "", []string{"{precision}", strconv.Itoa(precision), "{scale}", strconv.Itoa(scale)}
+	case within:
+		limit, err := time.ParseDuration(param)
+		if err != nil {
+			return name
+		}
+		param = limit.String()
+	case requiredIf, requiredIfAny:
+		pairs := strings.Fields(param)
+		var conditions []string
+		for i := 0; i+1 < len(pairs); i += 2 {
+			conditions = append(conditions, fmt.Sprintf("%s is %s", pairs[i], pairs[i+1]))
+		}
+		param = conditionsParam(name, conditions)
+	case uniqueWith:
+		param = strings.Join(strings.Fields(param), " and ")
+	}
+	return defaultMessage(code, param, placeholders...).Error()
+}
+
+// markdownRules renders docs as a Markdown table
+func markdownRules(docs []RuleDoc) string {
+	var sb strings.Builder
+	sb.WriteString("| Field | Type | Rule | Parameter | Code | Message |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, doc := range docs {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCell(doc.Field, true), markdownCell(doc.Type, true), markdownCell(doc.Rule, true),
+			markdownCell(doc.Param, true), markdownCell(doc.Code, true), markdownCell(doc.Message, false))
+	}
+	return sb.String()
+}
+
+// markdownCell escapes the pipes of a table cell and keeps it on one line, code cells are written
+// as code spans fenced with more backticks than they hold
+func markdownCell(text string, code bool) string {
+	if text == "" {
+		return ""
+	}
+	text = strings.ReplaceAll(text, "|", `\|`)
+	if !code {
+		return strings.NewReplacer("\r\n", "<br>", "\n", "<br>", "\r", "<br>", "`", "\\`").Replace(text)
+	}
+	// code spans show <br> as written
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(text)
+	fence := "`"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	if len(fence) > 1 {
+		// the spaces keep backticks at the ends of text apart from the fence
+		return fence + " " + text + " " + fence
+	}
+	return fence + text + fence
+}
@@ -0,0 +1,47 @@
+package validator
+
+import "testing"
+
+type documentedParams struct {
+	Name string `validate:"min=abc,max=10,range=4:1,decimal=8,digits=0,within=soon"`
+}
+
+func TestRuleMessagesOfUnreadableParams(t *testing.T) {
+	docs, err := New().DescribeRules(&documentedParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		min:       min,
+		max:       "length must be at most 10",
+		rangeRule: rangeRule,
+		decimal:   decimal,
+		digits:    digits,
+		within:    within,
+	}
+	for _, doc := range docs {
+		if doc.Message != want[doc.Rule] {
+			t.Errorf("%s message = %q, want %q", doc.Rule, doc.Message, want[doc.Rule])
+		}
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	tests := []struct {
+		text string
+		code bool
+		want string
+	}{
+		{text: "a|b", code: true, want: "`a\\|b`"},
+		{text: "^`a`$", code: true, want: "`` ^`a`$ ``"},
+		{text: "a``b", code: true, want: "``` a``b ```"},
+		{text: "first\nsecond", code: true, want: "`first second`"},
+		{text: "first\r\nsecond", want: "first<br>second"},
+		{text: "use `x`", want: "use \\`x\\`"},
+	}
+	for _, tt := range tests {
+		if got := markdownCell(tt.text, tt.code); got != tt.want {
+			t.Errorf("markdownCell(%q, %t) = %q, want %q", tt.text, tt.code, got, tt.want)
+		}
+	}
+}
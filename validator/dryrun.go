@@ -0,0 +1,32 @@
+package validator
+
+import "context"
+
+// DryRunReport is the outcome of DryRun
+type DryRunReport struct {
+	// Warnings are the rule failures Validate would have returned
+	Warnings ValidationErrors
+	// Err is set when no rules could be evaluated, for an invalid target or an *InvalidRuleError
+	Err error
+}
+
+// DryRun evaluates every rule against s like Validate, but returns the failures as warnings instead of
+// an error, so new and stricter rules can be shadow-rolled out against production traffic.
+// Hooks, metrics, logging and tracing observe the run as usual.
+func (v *Validator) DryRun(s interface{}, opts ...Option) DryRunReport {
+	return v.DryRunContext(context.Background(), s, opts...)
+}
+
+// DryRunContext is DryRun with a context handed to the logger and tracer
+func (v *Validator) DryRunContext(ctx context.Context, s interface{}, opts ...Option) DryRunReport {
+	err := v.ValidateContext(ctx, s, opts...)
+	if errs, ok := err.(ValidationErrors); ok {
+		return DryRunReport{Warnings: errs}
+	}
+	return DryRunReport{Err: err}
+}
+
+// Passed reports whether the dry run found neither warnings nor errors
+func (r DryRunReport) Passed() bool {
+	return len(r.Warnings) == 0 && r.Err == nil
+}
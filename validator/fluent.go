@@ -0,0 +1,621 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// customRule is the rule name reported for Must and Custom checks
+const customRule = "custom"
+
+// FluentValidator holds rules declared in code for values of type T, as an alternative to struct tags
+type FluentValidator[T any] struct {
+	engine   *Validator
+	rules    []fluentRule[T]
+	preHooks []func(instance *T) (skip bool, err error)
+	// ownOptions keeps the engine and defaults of the validator when included, see InheritOptions
+	ownOptions bool
+}
+
+// fluentRule is a rule chain declared with RuleFor
+type fluentRule[T any] interface {
+	validate(call fluentCall, instance *T) (ValidationErrors, error)
+}
+
+// fluentCall is what the rules of a FluentValidator run with: the engine giving the messages and the
+// casing of field names, and the options of the Validate call
+type fluentCall struct {
+	engine *Validator
+	opts   callOptions
+}
+
+// stop reports whether validation ends with errs, at the first failure under WithFailFast
+func (c fluentCall) stop(errs ValidationErrors) bool {
+	return c.opts.failFast && len(errs) > 0
+}
+
+// RuleBuilder chains the checks applied to the field selected by RuleFor, or to each element of a slice for ForEach
+type RuleBuilder[T, F any] struct {
+	owner    *FluentValidator[T]
+	name     string
+	selector func(*T) F
+	elements func(*T) []F // set by ForEach, the checks then apply to each element
+	checks   []fluentCheck[T, F]
+	children *FluentValidator[F] // rules declared with ChildRules
+	// included are the validators given to SetValidator
+	included []*FluentValidator[F]
+}
+
+// fluentCheck is a single check of a RuleBuilder, either a built-in rule or a function
+type fluentCheck[T, F any] struct {
+	rule    string            // built-in rule in tag syntax ex: min=2
+	fn      func(*T, F) error // used when rule is empty, receives the instance for cross-field checks
+	fnName  string            // rule name reported for fn
+	message string            // replaces the check's own message when set
+}
+
+// NewFluentValidator creates a FluentValidator for T
+func NewFluentValidator[T any]() *FluentValidator[T] {
+	return &FluentValidator[T]{engine: New()}
+}
+
+// RuleFor starts a rule chain for the field returned by selector ex:
+//
+//	validator.RuleFor(fv, func(u *User) string { return u.Email }).EmailAddress()
+//
+// The selector is checked by the compiler, so renaming the field breaks the build instead of the rule.
+// The reported field name is inferred from the selector when it returns a field as is, computed
+// values have to be named with WithName. Go methods cannot take type parameters, hence the function.
+func RuleFor[T, F any](fv *FluentValidator[T], selector func(*T) F) *RuleBuilder[T, F] {
+	rb := &RuleBuilder[T, F]{
+		owner:    fv,
+		name:     selectedFieldName(selector),
+		selector: selector,
+	}
+	fv.rules = append(fv.rules, rb)
+	return rb
+}
+
+// RuleForFunc starts a rule chain for the value a method returns, named after the method, so computed
+// properties are validated without a field of their own:
+//
+//	validator.RuleForFunc(fv, (*User).FullName).NotEmpty().Min(5)
+//
+// Methods with a value receiver are given through the pointer type as well. Other functions have to
+// be named with WithName.
+func RuleForFunc[T, F any](fv *FluentValidator[T], method func(*T) F) *RuleBuilder[T, F] {
+	rb := &RuleBuilder[T, F]{
+		owner:    fv,
+		name:     methodName(method),
+		selector: method,
+	}
+	fv.rules = append(fv.rules, rb)
+	return rb
+}
+
+// ForEach starts a rule chain applied to every element of the slice selected by collection, errors are
+// reported per element ex: Items[2]. The checks of collection itself still apply to the whole slice:
+//
+//	validator.ForEach(validator.RuleFor(fv, func(o *Order) []Item { return o.Items }).NotEmpty()).
+//		ChildRules(func(item *validator.FluentValidator[Item]) {
+//			validator.RuleFor(item, func(i *Item) string { return i.SKU }).NotEmpty()
+//		})
+func ForEach[T, E any](collection *RuleBuilder[T, []E]) *RuleBuilder[T, E] {
+	rb := &RuleBuilder[T, E]{
+		owner:    collection.owner,
+		name:     collection.name,
+		elements: collection.selector,
+	}
+	collection.owner.rules = append(collection.owner.rules, rb)
+	return rb
+}
+
+// ChildRules validates the selected struct, or each element for ForEach, with the rules declared by
+// build on a child FluentValidator. Child errors are reported under the field ex: Items[2].SKU
+func (rb *RuleBuilder[T, F]) ChildRules(build func(child *FluentValidator[F])) *RuleBuilder[T, F] {
+	if rb.children == nil {
+		rb.children = &FluentValidator[F]{engine: rb.owner.engine}
+	}
+	build(rb.children)
+	return rb
+}
+
+// SetValidator validates the selected value, or each element for ForEach, with child, a validator
+// declared on its own and reused ex: an AddressValidator shared by several parents. Child errors are
+// reported under the field like ChildRules, with the options of the parent unless child opted out
+// with InheritOptions(false).
+func (rb *RuleBuilder[T, F]) SetValidator(child *FluentValidator[F]) *RuleBuilder[T, F] {
+	rb.included = append(rb.included, child)
+	return rb
+}
+
+// SetCollectionValidator validates every element of the slice selected by collection with child,
+// like ForEach followed by SetValidator
+func SetCollectionValidator[T, E any](collection *RuleBuilder[T, []E], child *FluentValidator[E]) *RuleBuilder[T, E] {
+	return ForEach(collection).SetValidator(child)
+}
+
+// UseValidator runs the rules with v instead of a Validator of their own, for its messages
+// translated with RegisterMessage, its locale and its field name casing
+func (fv *FluentValidator[T]) UseValidator(v *Validator) *FluentValidator[T] {
+	fv.engine = v
+	return fv
+}
+
+// InheritOptions chooses whether fv, once included in another validator with SetValidator, runs
+// with the options of its parent: the locale and WithFailFast of the call, and the messages and
+// field name casing of the parent's Validator. Inheriting is the default, so nested errors come out
+// in the locale of the parent. With false, fv keeps its own Validator and its defaults.
+func (fv *FluentValidator[T]) InheritOptions(inherit bool) *FluentValidator[T] {
+	fv.ownOptions = !inherit
+	return fv
+}
+
+// PreValidate registers a hook run before the rules, also for a nil instance. skip stops validation with
+// no errors, a non nil err stops it with err as the only result, like Validator.OnPreValidate.
+func (fv *FluentValidator[T]) PreValidate(fn func(instance *T) (skip bool, err error)) *FluentValidator[T] {
+	fv.preHooks = append(fv.preHooks, fn)
+	return fv
+}
+
+// Validate runs the declared rules against instance. Of the options, WithLocale and WithFailFast
+// apply, to the validators included with SetValidator too.
+func (fv *FluentValidator[T]) Validate(instance *T, opts ...Option) error {
+	errs, err := fv.run(fv.ownCall(opts), instance)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ownCall returns the configuration of a call made with opts on the engine of fv
+func (fv *FluentValidator[T]) ownCall(opts []Option) fluentCall {
+	return fluentCall{engine: fv.engine, opts: fv.engine.newCallOptions(opts)}
+}
+
+// run runs the hooks and the declared rules against instance with call
+func (fv *FluentValidator[T]) run(call fluentCall, instance *T) (ValidationErrors, error) {
+	for _, hook := range fv.preHooks {
+		if skip, err := hook(instance); skip || err != nil {
+			return nil, err
+		}
+	}
+	if instance == nil {
+		return nil, fmt.Errorf("%w: validation requires a non nil pointer", ErrNilValue)
+	}
+
+	var errs ValidationErrors
+	for _, rule := range fv.rules {
+		ruleErrs, err := rule.validate(call, instance)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, ruleErrs...)
+		if call.stop(errs) {
+			return errs[:1], nil
+		}
+	}
+	return errs, nil
+}
+
+// WithName sets the field name used in errors, required for selectors returning computed values
+func (rb *RuleBuilder[T, F]) WithName(name string) *RuleBuilder[T, F] {
+	rb.name = name
+	return rb
+}
+
+// WithMessage replaces the message of the previous check
+func (rb *RuleBuilder[T, F]) WithMessage(message string) *RuleBuilder[T, F] {
+	if len(rb.checks) > 0 {
+		rb.checks[len(rb.checks)-1].message = message
+	}
+	return rb
+}
+
+// NotEmpty requires a non zero value, like the required tag
+func (rb *RuleBuilder[T, F]) NotEmpty() *RuleBuilder[T, F] {
+	return rb.addRule(required)
+}
+
+// EmailAddress requires a valid email, like the email tag
+func (rb *RuleBuilder[T, F]) EmailAddress() *RuleBuilder[T, F] {
+	return rb.addRule(email)
+}
+
+// Min requires a minimum length or value, like the min tag
+func (rb *RuleBuilder[T, F]) Min(n int) *RuleBuilder[T, F] {
+	return rb.addRule(min + "=" + strconv.Itoa(n))
+}
+
+// Max requires a maximum length or value, like the max tag
+func (rb *RuleBuilder[T, F]) Max(n int) *RuleBuilder[T, F] {
+	return rb.addRule(max + "=" + strconv.Itoa(n))
+}
+
+// Matches requires the value to match pattern, like the regex tag
+func (rb *RuleBuilder[T, F]) Matches(pattern string) *RuleBuilder[T, F] {
+	return rb.addRule(regex + "=" + quoteParam(pattern))
+}
+
+// Must fails with message when predicate returns false
+func (rb *RuleBuilder[T, F]) Must(predicate func(value F) bool, message string) *RuleBuilder[T, F] {
+	return rb.Custom(func(value F) error {
+		if !predicate(value) {
+			return errors.New(message)
+		}
+		return nil
+	})
+}
+
+// Custom fails with the error returned by fn
+func (rb *RuleBuilder[T, F]) Custom(fn func(value F) error) *RuleBuilder[T, F] {
+	return rb.addCheck(customRule, func(_ *T, value F) error {
+		return fn(value)
+	})
+}
+
+// Equal requires the value to equal the one returned by other ex: .Equal(func(u *User) string { return u.Password })
+func (rb *RuleBuilder[T, F]) Equal(other func(*T) F) *RuleBuilder[T, F] {
+	return rb.compareWith("equal", other, "must be equal to %s", func(c int) bool { return c == 0 }, false)
+}
+
+// NotEqual requires the value to differ from the one returned by other
+func (rb *RuleBuilder[T, F]) NotEqual(other func(*T) F) *RuleBuilder[T, F] {
+	return rb.compareWith("not_equal", other, "must not be equal to %s", func(c int) bool { return c != 0 }, false)
+}
+
+// GreaterThan requires the value to be greater than the one returned by other
+func (rb *RuleBuilder[T, F]) GreaterThan(other func(*T) F) *RuleBuilder[T, F] {
+	return rb.compareWith("greater_than", other, "must be greater than %s", func(c int) bool { return c > 0 }, true)
+}
+
+// LessThan requires the value to be less than the one returned by other
+func (rb *RuleBuilder[T, F]) LessThan(other func(*T) F) *RuleBuilder[T, F] {
+	return rb.compareWith("less_than", other, "must be less than %s", func(c int) bool { return c < 0 }, true)
+}
+
+// EqualField requires the value to equal the named field of the instance
+func (rb *RuleBuilder[T, F]) EqualField(field string) *RuleBuilder[T, F] {
+	return rb.compareWithField("equal_field", field, "must be equal to %s", func(c int) bool { return c == 0 }, false)
+}
+
+// GreaterThanField requires the value to be greater than the named field of the instance
+func (rb *RuleBuilder[T, F]) GreaterThanField(field string) *RuleBuilder[T, F] {
+	return rb.compareWithField("greater_than_field", field, "must be greater than %s", func(c int) bool { return c > 0 }, true)
+}
+
+// LessThanField requires the value to be less than the named field of the instance ex: .LessThanField("Max")
+func (rb *RuleBuilder[T, F]) LessThanField(field string) *RuleBuilder[T, F] {
+	return rb.compareWithField("less_than_field", field, "must be less than %s", func(c int) bool { return c < 0 }, true)
+}
+
+func (rb *RuleBuilder[T, F]) compareWith(ruleName string, other func(*T) F, format string, accept func(int) bool, ordered bool) *RuleBuilder[T, F] {
+	otherName := selectedFieldName(other)
+	if otherName == "" {
+		otherName = "the compared value"
+	}
+	return rb.addCheck(ruleName, func(instance *T, value F) error {
+		otherValue := other(instance)
+		return compareFieldValues(rb.name, ruleName, reflect.ValueOf(&value).Elem(), reflect.ValueOf(&otherValue).Elem(), otherName, format, accept, ordered)
+	})
+}
+
+func (rb *RuleBuilder[T, F]) compareWithField(ruleName, field, format string, accept func(int) bool, ordered bool) *RuleBuilder[T, F] {
+	return rb.addCheck(ruleName, func(instance *T, value F) error {
+		otherVal, ok := fieldByName(reflect.ValueOf(instance), field)
+		if !ok {
+			return &InvalidRuleError{Field: rb.name, Rule: ruleName, Reason: fmt.Sprintf("unknown field %s", field)}
+		}
+		return compareFieldValues(rb.name, ruleName, reflect.ValueOf(&value).Elem(), otherVal, field, format, accept, ordered)
+	})
+}
+
+func (rb *RuleBuilder[T, F]) addRule(rule string) *RuleBuilder[T, F] {
+	rb.checks = append(rb.checks, fluentCheck[T, F]{rule: rule})
+	return rb
+}
+
+func (rb *RuleBuilder[T, F]) addCheck(name string, fn func(*T, F) error) *RuleBuilder[T, F] {
+	rb.checks = append(rb.checks, fluentCheck[T, F]{fn: fn, fnName: name})
+	return rb
+}
+
+// compareFieldValues fails with format when accept rejects the comparison of value with other.
+// Equality compares any values, ordered comparisons need numbers, strings or time.Time.
+func compareFieldValues(fieldName, ruleName string, value, other reflect.Value, otherName, format string, accept func(int) bool, ordered bool) error {
+	var c int
+	if ordered {
+		var ok bool
+		if c, ok = compareValues(value, other); !ok {
+			return &InvalidRuleError{Field: fieldName, Rule: ruleName, Reason: fmt.Sprintf("cannot order %s and %s", value.Type(), other.Type())}
+		}
+	} else if !reflect.DeepEqual(value.Interface(), other.Interface()) {
+		c = 1
+	}
+
+	if !accept(c) {
+		return fmt.Errorf(format, otherName)
+	}
+	return nil
+}
+
+// compareValues orders two numbers, strings or times, returning -1, 0 or 1
+func compareValues(a, b reflect.Value) (int, bool) {
+	sign := func(diff float64) int {
+		switch {
+		case diff < 0:
+			return -1
+		case diff > 0:
+			return 1
+		}
+		return 0
+	}
+
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), true
+	}
+
+	af, aok := numericValue(a)
+	bf, bok := numericValue(b)
+	if aok && bok {
+		return sign(af - bf), true
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	return 0, false
+}
+
+// numericValue returns a number of any kind as a float64
+func numericValue(val reflect.Value) (float64, bool) {
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
+	return 0, false
+}
+
+func (rb *RuleBuilder[T, F]) validate(call fluentCall, instance *T) (ValidationErrors, error) {
+	name := call.opts.pathName(rb.name)
+	if rb.elements == nil {
+		return rb.validateValue(call, instance, rb.selector(instance), name, -1)
+	}
+
+	var errs ValidationErrors
+	for i, elem := range rb.elements(instance) {
+		elemErrs, err := rb.validateValue(call, instance, elem, elemName(name, i), i)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, elemErrs...)
+		if call.stop(errs) {
+			return errs, nil
+		}
+	}
+	return errs, nil
+}
+
+// validateValue runs the checks and the child rules against value, named fieldName in errors.
+// index is the element position for ForEach, -1 otherwise.
+func (rb *RuleBuilder[T, F]) validateValue(call fluentCall, instance *T, value F, fieldName string, index int) (ValidationErrors, error) {
+	engine := call.engine
+	// going through a pointer keeps the static kind of interface typed fields
+	fieldVal := reflect.ValueOf(&value).Elem()
+
+	var errs ValidationErrors
+	for _, check := range rb.checks {
+		var err error
+		ruleName, param := check.fnName, ""
+		if check.rule != "" {
+			ruleName, param = parseRule(check.rule)
+			if kindErr := checkRuleKind(check.rule, fieldVal, fieldName); kindErr != nil {
+				return nil, kindErr
+			}
+			err = engine.applyValidationRule(check.rule, fieldVal, reflect.ValueOf(instance).Elem(), fieldName)
+			if _, isRuleErr := err.(*InvalidRuleError); isRuleErr {
+				return nil, err
+			}
+		} else {
+			err = check.fn(instance, value)
+			if _, isRuleErr := err.(*InvalidRuleError); isRuleErr {
+				return nil, err
+			}
+		}
+		if err == nil {
+			continue
+		}
+
+		valErr := ValidationError{
+			Field:   fieldName,
+			Message: err.Error(),
+			Rule:    ruleName,
+			Code:    errorCode(ruleName, fieldVal),
+			index:   index,
+			isElem:  index >= 0,
+		}
+		// a message given with WithMessage is kept as written
+		valErr.Message = engine.message(call.opts.locale, valErr, param)
+		if check.message != "" {
+			valErr.Message = check.message
+		}
+		errs = append(errs, valErr)
+		if call.stop(errs) {
+			return errs, nil
+		}
+	}
+
+	var children []fluentRule[F]
+	if rb.children != nil {
+		children = rb.children.rules
+	}
+	for _, rule := range children {
+		childErrs, err := rule.validate(call, &value)
+		if err != nil {
+			return nil, err
+		}
+		if errs = appendChildErrors(errs, fieldName, childErrs); call.stop(errs) {
+			return errs, nil
+		}
+	}
+	for _, child := range rb.included {
+		childCall := call
+		if child.ownOptions {
+			childCall = child.ownCall(nil)
+		}
+		childErrs, err := child.run(childCall, &value)
+		if err != nil {
+			return nil, err
+		}
+		if errs = appendChildErrors(errs, fieldName, childErrs); call.stop(errs) {
+			return errs, nil
+		}
+	}
+	return errs, nil
+}
+
+// appendChildErrors appends the errors of a child validator to errs, under fieldName ex: Items[2].SKU
+func appendChildErrors(errs ValidationErrors, fieldName string, childErrs ValidationErrors) ValidationErrors {
+	for _, childErr := range childErrs {
+		childErr.Field = fieldPath(fieldName, childErr.Field)
+		errs = append(errs, childErr)
+	}
+	return errs
+}
+
+// selectedFieldName finds the field returned by selector by setting the fields of a probe value one
+// at a time and watching which one changes the selector's result. Nested fields are reported with
+// their path ex: Address.City. It returns "" for computed values or selectors that panic on the probe.
+func selectedFieldName[T, F any](selector func(*T) F) (name string) {
+	defer func() {
+		if recover() != nil {
+			name = ""
+		}
+	}()
+
+	probe := new(T)
+	base := selector(probe)
+	probeVal := reflect.ValueOf(probe).Elem()
+	if probeVal.Kind() != reflect.Struct {
+		return ""
+	}
+
+	changed := func() bool {
+		return !reflect.DeepEqual(selector(probe), base)
+	}
+	return probeStruct(probeVal, reflect.TypeOf((*F)(nil)).Elem(), "", changed)
+}
+
+// probeStruct looks for the field of structVal whose change is observed by changed
+func probeStruct(structVal reflect.Value, selected reflect.Type, prefix string, changed func() bool) string {
+	structType := structVal.Type()
+
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		name := fieldPath(prefix, structType.Field(i).Name)
+
+		// a nested struct is either selected as a whole or one of its fields is
+		if field.Kind() == reflect.Struct && field.Type() != timeType {
+			nested := probeStruct(field, selected, name, changed)
+			if field.Type() != selected {
+				if nested != "" {
+					return nested
+				}
+				continue
+			}
+			// structs with no settable fields cannot be probed, accept them when unambiguous
+			if nested != "" || countFieldsOfType(structType, selected) == 1 {
+				return name
+			}
+			continue
+		}
+
+		marker, ok := nonZeroValue(field.Type())
+		if !ok {
+			continue
+		}
+		original := reflect.New(field.Type()).Elem()
+		original.Set(field)
+		field.Set(marker)
+		found := changed()
+		field.Set(original)
+		if found {
+			return name
+		}
+	}
+	return ""
+}
+
+// countFieldsOfType counts the fields of structType with type t
+func countFieldsOfType(structType reflect.Type, t reflect.Type) int {
+	count := 0
+	for i := 0; i < structType.NumField(); i++ {
+		if structType.Field(i).Type == t {
+			count++
+		}
+	}
+	return count
+}
+
+// nonZeroValue returns a value of type t that differs from its zero value
+func nonZeroValue(t reflect.Type) (reflect.Value, bool) {
+	value := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		value.SetString("x")
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(1)
+	case reflect.Complex64, reflect.Complex128:
+		value.SetComplex(1)
+	case reflect.Pointer:
+		value.Set(reflect.New(t.Elem()))
+	case reflect.Slice:
+		value.Set(reflect.MakeSlice(t, 1, 1))
+	case reflect.Map:
+		value.Set(reflect.MakeMap(t))
+	case reflect.Chan:
+		value.Set(reflect.MakeChan(t, 0))
+	case reflect.Array:
+		if t.Len() == 0 {
+			return value, false
+		}
+		elem, ok := nonZeroValue(t.Elem())
+		if !ok {
+			return value, false
+		}
+		value.Index(0).Set(elem)
+	case reflect.Struct:
+		if t != timeType {
+			return value, false
+		}
+		value.Set(reflect.ValueOf(time.Unix(1, 0)))
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			return value, false
+		}
+		value.Set(reflect.ValueOf(true))
+	default:
+		return value, false
+	}
+	return value, true
+}
@@ -0,0 +1,61 @@
+package validator
+
+type (
+	// PreValidateFunc decides whether target is validated at all. skip stops validation with no errors,
+	// a non nil err stops it with err as the only result ex: a nil DTO or a deleted entity.
+	PreValidateFunc func(target interface{}) (skip bool, err error)
+
+	// BeforeValidateFunc runs before the fields of target are validated, it may normalize target in place
+	BeforeValidateFunc func(target interface{})
+
+	// AfterValidateFunc runs once target has been validated, the errors it returns replace errs
+	AfterValidateFunc func(target interface{}, errs ValidationErrors) ValidationErrors
+)
+
+// OnPreValidate registers a hook called by Validate first, before the target is even checked to be a
+// struct pointer, so it also sees nil targets. Hooks run in registration order until one skips or fails.
+func (v *Validator) OnPreValidate(fn PreValidateFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.preHooks = append(v.preHooks, fn)
+}
+
+// OnBeforeValidate registers a hook called by Validate before any rule runs.
+// Hooks run in registration order and only for valid struct pointer targets.
+func (v *Validator) OnBeforeValidate(fn BeforeValidateFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.beforeHooks = append(v.beforeHooks, fn)
+}
+
+// OnAfterValidate registers a hook called by Validate with the errors found, an empty slice when the
+// target is valid. Hooks run in registration order, each receiving the errors returned by the previous one.
+// They are not called when validation stops on an *InvalidRuleError.
+func (v *Validator) OnAfterValidate(fn AfterValidateFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.afterHooks = append(v.afterHooks, fn)
+}
+
+// runPreHooks reports whether a PreValidate hook skipped target, and the error it stopped with
+func (s *settings) runPreHooks(target interface{}) (bool, error) {
+	for _, hook := range s.preHooks {
+		if skip, err := hook(target); skip || err != nil {
+			return true, err
+		}
+	}
+	return false, nil
+}
+
+func (s *settings) runBeforeHooks(target interface{}) {
+	for _, hook := range s.beforeHooks {
+		hook(target)
+	}
+}
+
+func (s *settings) runAfterHooks(target interface{}, errs ValidationErrors) ValidationErrors {
+	for _, hook := range s.afterHooks {
+		errs = hook(target, errs)
+	}
+	return errs
+}
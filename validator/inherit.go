@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// interfaceRules are field rules inherited by every struct implementing iface
+type interfaceRules struct {
+	iface      reflect.Type
+	fieldRules map[string]string
+}
+
+// RegisterInterfaceRules makes every struct implementing an interface, by value or by pointer, inherit
+// rules for its fields, given by field name in tag syntax. iface is a nil pointer to the interface
+// ex: v.RegisterInterfaceRules((*Identifiable)(nil), map[string]string{"ID": "required"})
+// Inherited rules run before the field's own rules. A struct implementing the interface without
+// one of the fields is reported as an *InvalidRuleError, fields tagged - stay skipped.
+func (v *Validator) RegisterInterfaceRules(iface interface{}, fieldRules map[string]string) {
+	ifaceType := reflect.TypeOf(iface)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Pointer || ifaceType.Elem().Kind() != reflect.Interface {
+		panic("validator: RegisterInterfaceRules expects a nil pointer to an interface ex: (*Identifiable)(nil)")
+	}
+
+	copied := make(map[string]string, len(fieldRules))
+	for field, rules := range fieldRules {
+		copied[field] = rules
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.interfaceRules = append(v.interfaceRules, interfaceRules{iface: ifaceType.Elem(), fieldRules: copied})
+}
+
+// inheritedRules returns the rules structVal inherits from the interfaces it implements, by field name
+func (v *Validator) inheritedRules(structVal reflect.Value, prefix string) (map[string][]string, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if len(v.interfaceRules) == 0 {
+		return nil, nil
+	}
+
+	structType := structVal.Type()
+	var inherited map[string][]string
+	for _, registered := range v.interfaceRules {
+		if !structType.Implements(registered.iface) && !reflect.PointerTo(structType).Implements(registered.iface) {
+			continue
+		}
+		for field, rules := range registered.fieldRules {
+			if _, ok := structType.FieldByName(field); !ok {
+				return nil, &InvalidRuleError{
+					Field:  fieldPath(prefix, field),
+					Rule:   rules,
+					Reason: fmt.Sprintf("%s implements %s but has no field %s", structType, registered.iface, field),
+				}
+			}
+			if inherited == nil {
+				inherited = make(map[string][]string)
+			}
+			inherited[field] = append(inherited[field], splitRules(rules)...)
+		}
+	}
+	return inherited, nil
+}
@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger is the logging interface used to report validation failures, *slog.Logger satisfies it
+type Logger interface {
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+// SetLogger makes Validate log every failed validation at level, with the struct type,
+// the failing field paths and the failed rules. A nil logger disables logging.
+func (v *Validator) SetLogger(logger Logger, level slog.Level) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.logger = logger
+	v.logLevel = level
+}
+
+func (o *callOptions) logFailure(ctx context.Context, s interface{}, err error) {
+	errs, ok := err.(ValidationErrors)
+	if o.logger == nil || !ok {
+		return
+	}
+
+	fields := make([]string, len(errs))
+	rules := make([]string, len(errs))
+	for i, errVal := range errs {
+		fields[i] = errVal.Field
+		rules[i] = errVal.Rule
+	}
+	o.logger.Log(ctx, o.logLevel, "validation failed",
+		slog.String("struct", typeName(s)),
+		slog.Int("errors", len(errs)),
+		slog.Any("fields", fields),
+		slog.Any("rules", rules),
+	)
+}
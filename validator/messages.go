@@ -0,0 +1,282 @@
+package validator
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// defaultLocale is the language of the built-in messages
+const defaultLocale = "en"
+
+// Keys of the built-in templates that are not error codes: the failures of values a rule cannot
+// read, reported under the code of the rule, and of negated rules without a template of their own
+const (
+	digitsOnlyMessage  = "digits_only"
+	numberMessage      = "number"
+	timeMessage        = "rfc3339_time"
+	regexInputMessage  = "regex_input"
+	negatedRuleMessage = "negated_rule"
+)
+
+// unnamedFieldMessage is the key of the bundled label replacing {field} for the values checked
+// without a field name ex: الحقل for ar
+const unnamedFieldMessage = "unnamed_field"
+
+// defaultMessages are the built-in templates of the rules, by error code. {param} is the parameter
+// as the message shows it ex: the options of oneof separated with commas, or the condition of
+// required_if, and the rules with two bounds name them ex: {low} and {high}.
+var defaultMessages = map[string]string{
+	CodeRequired:      "field is required",
+	CodeNotBlank:      "field must not be blank",
+	CodeMustBeDefault: "field must not be set",
+	CodeRequiredIf:    "field is required when {param}",
+	CodeRequiredIfAny: "field is required when {param}",
+	CodeMinLength:     "length must be at least {param}",
+	CodeMinValue:      "value must be at least {param}",
+	CodeMinItems:      "must contain at least {param} {plural:one=item|other=items}",
+	CodeMaxLength:     "length must be at most {param}",
+	CodeMaxValue:      "value must be at most {param}",
+	CodeMaxItems:      "must contain at most {param} {plural:one=item|other=items}",
+	CodeRangeLength:   "length must be between {low} and {high}",
+	CodeRangeValue:    "value must be between {low} and {high}",
+	CodeRangeItems:    "must contain between {low} and {high} items",
+	CodeDigits:        "must have exactly {param} digits",
+	CodeDecimal:       "must have at most {precision} integer digits and {scale} decimal places",
+	CodeEmail:         "invalid email format",
+	CodeRegex:         "value does not match required format",
+	CodeOneOf:         "must be one of: {param}",
+	CodeContains:      "must contain {param}",
+	CodeE164:          "invalid E.164 phone number",
+	CodeJSON:          "must be valid JSON",
+	CodeNotBeforeNow:  "must not be in the past",
+	CodeNotAfterNow:   "must not be in the future",
+	CodeWithin:        "must be within {param} of now",
+	CodeUniqueWith:    "must differ from {param}",
+
+	negatedCodePrefix + "REQUIRED":        "field must be empty",
+	negatedCodePrefix + "NOT_BLANK":       "field must be blank",
+	negatedCodePrefix + "MUST_BE_DEFAULT": "field must be set",
+	negatedCodePrefix + "EMAIL":           "must not be an email address",
+	negatedCodePrefix + "PATTERN":         "value must not match the format",
+	negatedCodePrefix + "ONE_OF":          "must not be one of: {param}",
+	negatedCodePrefix + "CONTAINS":        "must not contain {param}",
+	negatedCodePrefix + "JSON":            "must not be JSON",
+
+	digitsOnlyMessage:  "must contain digits only",
+	numberMessage:      "must be a number",
+	timeMessage:        "must be an RFC3339 time",
+	regexInputMessage:  "must be at most {param} bytes to be matched",
+	negatedRuleMessage: "must not satisfy {param}",
+}
+
+// bundledMessages are the translations shipped for the built-in rules, by language then error code.
+// Templates registered with RegisterMessage win over them.
+var bundledMessages = map[string]map[string]string{
+	arabicLocale: arabicMessages,
+}
+
+// pluralPrefix opens a plural choice in a message template ex: {plural:one=character|other=characters}
+const pluralPrefix = "{plural:"
+
+// otherForm is the CLDR plural category of a plural choice used when no other one matches
+const otherForm = "other"
+
+// RegisterMessage registers the message reported when rule fails and locale is selected with WithLocale or SetLocale.
+// rule is a rule name ex: min, or an error code for a finer choice ex: VAL_MIN_ITEMS, codes win over rule names.
+// {field} and {param} in the template are replaced by the field name and the rule parameter
+// ex: RegisterMessage("fr", "min", "{field} doit contenir au moins {param} caractères").
+// {plural:one=...|other=...} picks the text matching the plural category of a numeric parameter in
+// the locale, falling back to other ex: "at least {param} {plural:one=character|other=characters}".
+// Templates of a language ex: ar apply to its regional locales ex: ar-EG unless they have their own.
+func (v *Validator) RegisterMessage(locale, rule, template string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.messages[locale] == nil {
+		v.messages[locale] = make(map[string]string)
+	}
+	v.messages[locale][rule] = template
+}
+
+// message returns the message of valErr in locale, its own message when no template is registered
+func (v *Validator) message(locale string, valErr ValidationError, param string) string {
+	if locale == "" {
+		return valErr.Message
+	}
+
+	var template string
+	ok := false
+	v.mu.RLock()
+	// templates of a language ex: ar apply to its regional locales ex: ar-EG unless they have their own
+	for _, loc := range localeChain(locale) {
+		if template, ok = v.messages[loc][valErr.Code]; !ok {
+			template, ok = v.messages[loc][valErr.Rule]
+		}
+		if ok {
+			break
+		}
+	}
+	label := v.fieldLabel(locale, valErr.Field)
+	v.mu.RUnlock()
+	if !ok {
+		template, ok = bundledMessage(locale, valErr.Code)
+	}
+	if !ok {
+		return valErr.Message
+	}
+	if label == "" {
+		// the values checked with Check have no field name
+		label, _ = bundledMessage(locale, unnamedFieldMessage)
+	}
+	template = pluralize(locale, template, param)
+	return strings.NewReplacer("{field}", label, "{param}", formatParam(locale, param)).Replace(template)
+}
+
+// RegisterFieldTranslation registers the label replacing {field} in the messages of locale ex:
+// RegisterFieldTranslation("Email", "ar", "البريد الإلكتروني"), so messages shown to end users are
+// localized as a whole. field is a field name, matching the field wherever it is nested, or a path
+// ex: Address.City for one field only, element indexes left out. Labels of a language ex: ar apply
+// to its regional locales ex: ar-EG unless they have their own.
+func (v *Validator) RegisterFieldTranslation(field, locale, label string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.labels[locale] == nil {
+		v.labels[locale] = make(map[string]string)
+	}
+	v.labels[locale][field] = label
+}
+
+// fieldLabel returns the label of the field at path in locale, path itself without one. The path
+// wins over the field name, and the names of nested paths are the last ones ex: City for
+// Home.City or Tags for Tags[2]. Callers hold v.mu.
+func (v *Validator) fieldLabel(locale, path string) string {
+	if len(v.labels) == 0 || path == "" {
+		return path
+	}
+	// element indexes and map keys are left out of the registered paths
+	plain := stripIndexes(path)
+	name := plain[strings.LastIndexByte(plain, '.')+1:]
+	for _, candidate := range []string{plain, name} {
+		for _, loc := range localeChain(locale) {
+			for field, label := range v.labels[loc] {
+				// registered names are matched with the casing of the reported ones
+				if field == candidate || v.pathName(field) == candidate {
+					return label
+				}
+			}
+		}
+	}
+	return path
+}
+
+// stripIndexes removes the element indexes and map keys of a path ex: Items.SKU for Items[2].SKU
+func stripIndexes(path string) string {
+	if !strings.Contains(path, "[") {
+		return path
+	}
+	var out strings.Builder
+	depth := 0
+	quoted := false
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case quoted:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quoted = false
+			}
+		case c == '"' && depth > 0:
+			quoted = true
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case depth == 0:
+			out.WriteByte(c)
+		}
+	}
+	return strings.TrimPrefix(out.String(), ".")
+}
+
+// localeChain returns locale followed by its language when it is a regional locale ex: ar-EG, ar
+func localeChain(locale string) []string {
+	if base, ok := baseLanguage(locale); ok && base != locale {
+		return []string{locale, base}
+	}
+	return []string{locale}
+}
+
+// bundledMessage returns the shipped template of code for the language of locale ex: ar for ar-EG
+func bundledMessage(locale, code string) (string, bool) {
+	base, ok := baseLanguage(locale)
+	if !ok {
+		return "", false
+	}
+	template, ok := bundledMessages[base][code]
+	return template, ok
+}
+
+// SetLocale selects the locale of the messages reported by every call ex: v.SetLocale("ar") for the
+// bundled Arabic messages, WithLocale overrides it for a single call. An empty locale restores the default messages.
+func (v *Validator) SetLocale(locale string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.locale = locale
+}
+
+// defaultMessage renders the built-in template of code: param replaces {param} and chooses the
+// plural forms, placeholders holds the values of the other placeholders in pairs ex: "{low}", "18"
+func defaultMessage(code, param string, placeholders ...string) error {
+	template := strings.ReplaceAll(pluralize(defaultLocale, defaultMessages[code], param), "{param}", param)
+	for i := 0; i+1 < len(placeholders); i += 2 {
+		template = strings.ReplaceAll(template, placeholders[i], placeholders[i+1])
+	}
+	return errors.New(template)
+}
+
+// listParam shows the space separated options of a parameter ex: a, b, c for oneof=a b c
+func listParam(param string) string {
+	return strings.Join(strings.Fields(param), ", ")
+}
+
+// boundParam shows a bound of range as fmt's %v does ex: 18 or 1.5
+func boundParam(bound float64) string {
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
+
+// pluralize resolves the plural choices of template for the count held by param
+func pluralize(locale, template, param string) string {
+	form := pluralForm(locale, param)
+
+	var out strings.Builder
+	for {
+		start := strings.Index(template, pluralPrefix)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(template[start:], "}")
+		if end < 0 {
+			break
+		}
+		out.WriteString(template[:start])
+		out.WriteString(pluralChoice(template[start+len(pluralPrefix):start+end], form))
+		template = template[start+end+1:]
+	}
+	out.WriteString(template)
+	return out.String()
+}
+
+// pluralChoice returns the text of form in choices ex: one=character|other=characters, or the text of other
+func pluralChoice(choices, form string) string {
+	var other string
+	for _, choice := range strings.Split(choices, "|") {
+		name, text, _ := strings.Cut(choice, "=")
+		switch strings.Trim(name, " ") {
+		case form:
+			return text
+		case otherForm:
+			other = text
+		}
+	}
+	return other
+}
@@ -0,0 +1,59 @@
+package validator
+
+// arabicLocale selects the bundled Arabic messages ex: v.SetLocale("ar")
+const arabicLocale = "ar"
+
+// Parameters and Latin terms are wrapped in first strong isolate and pop directional isolate marks, so
+// field names, numbers and lists keep their order inside right to left text
+const (
+	firstStrongIsolate  = "\u2068"
+	popDirectionIsolate = "\u2069"
+	isolatedParam       = firstStrongIsolate + "{param}" + popDirectionIsolate
+	isolatedField       = firstStrongIsolate + "{field}" + popDirectionIsolate
+	arabicCharacters    = "{plural:zero=حرف|one=حرف|two=حرفين|few=أحرف|many=حرفًا|other=حرف}"
+	arabicItems         = "{plural:zero=عنصر|one=عنصر|two=عنصرين|few=عناصر|many=عنصرًا|other=عنصر}"
+	arabicDigits        = "{plural:zero=رقم|one=رقم|two=رقمين|few=أرقام|many=رقمًا|other=رقم}"
+	arabicIsolatedE164  = firstStrongIsolate + "E.164" + popDirectionIsolate
+	arabicIsolatedJSON  = firstStrongIsolate + "JSON" + popDirectionIsolate
+)
+
+// arabicMessages are the templates of the built-in rules for ar, by error code
+var arabicMessages = map[string]string{
+	unnamedFieldMessage: "الحقل",
+
+	CodeRequired:      isolatedField + " مطلوب",
+	CodeNotBlank:      "يجب ألا يكون " + isolatedField + " فارغًا",
+	CodeMustBeDefault: "يجب ترك " + isolatedField + " دون قيمة",
+	CodeRequiredIf:    isolatedField + " مطلوب عند تحقق الشرط " + isolatedParam,
+	CodeRequiredIfAny: isolatedField + " مطلوب عند تحقق أي من الشروط " + isolatedParam,
+	CodeMinLength:     "يجب ألا يقل طول " + isolatedField + " عن " + isolatedParam + " " + arabicCharacters,
+	CodeMinValue:      "يجب ألا تقل قيمة " + isolatedField + " عن " + isolatedParam,
+	CodeMinItems:      "يجب ألا يقل عدد عناصر " + isolatedField + " عن " + isolatedParam + " " + arabicItems,
+	CodeMaxLength:     "يجب ألا يتجاوز طول " + isolatedField + " " + isolatedParam + " " + arabicCharacters,
+	CodeMaxValue:      "يجب ألا تتجاوز قيمة " + isolatedField + " " + isolatedParam,
+	CodeMaxItems:      "يجب ألا يتجاوز عدد عناصر " + isolatedField + " " + isolatedParam + " " + arabicItems,
+	CodeRangeLength:   "يجب أن يكون طول " + isolatedField + " ضمن النطاق " + isolatedParam,
+	CodeRangeValue:    "يجب أن تكون قيمة " + isolatedField + " ضمن النطاق " + isolatedParam,
+	CodeRangeItems:    "يجب أن يكون عدد عناصر " + isolatedField + " ضمن النطاق " + isolatedParam,
+	CodeDigits:        "يجب أن يتكون " + isolatedField + " من " + isolatedParam + " " + arabicDigits + " فقط",
+	CodeDecimal:       "يجب أن يكون " + isolatedField + " رقمًا لا يتجاوز الدقة " + isolatedParam,
+	CodeEmail:         "صيغة البريد الإلكتروني في " + isolatedField + " غير صالحة",
+	CodeRegex:         "قيمة " + isolatedField + " لا تطابق الصيغة المطلوبة",
+	CodeOneOf:         "يجب أن تكون قيمة " + isolatedField + " إحدى: " + isolatedParam,
+	CodeContains:      "يجب أن يحتوي " + isolatedField + " على " + isolatedParam,
+	CodeE164:          "رقم الهاتف في " + isolatedField + " غير صالح وفق صيغة " + arabicIsolatedE164,
+	CodeJSON:          "يجب أن يكون " + isolatedField + " " + arabicIsolatedJSON + " صالحًا",
+	CodeNotBeforeNow:  "يجب ألا يكون وقت " + isolatedField + " في الماضي",
+	CodeNotAfterNow:   "يجب ألا يكون وقت " + isolatedField + " في المستقبل",
+	CodeWithin:        "يجب أن يكون وقت " + isolatedField + " في حدود " + isolatedParam + " من الآن",
+	CodeUniqueWith:    "يجب أن تختلف قيمة " + isolatedField + " عن " + isolatedParam,
+
+	negatedCodePrefix + "REQUIRED":        "يجب أن يكون " + isolatedField + " فارغًا",
+	negatedCodePrefix + "NOT_BLANK":       "يجب أن يكون " + isolatedField + " خاليًا",
+	negatedCodePrefix + "MUST_BE_DEFAULT": "يجب تعيين قيمة في " + isolatedField,
+	negatedCodePrefix + "EMAIL":           "يجب ألا يكون " + isolatedField + " بريدًا إلكترونيًا",
+	negatedCodePrefix + "PATTERN":         "يجب ألا تطابق قيمة " + isolatedField + " الصيغة",
+	negatedCodePrefix + "ONE_OF":          "يجب ألا تكون قيمة " + isolatedField + " إحدى: " + isolatedParam,
+	negatedCodePrefix + "CONTAINS":        "يجب ألا يحتوي " + isolatedField + " على " + isolatedParam,
+	negatedCodePrefix + "JSON":            "يجب ألا يكون " + isolatedField + " " + arabicIsolatedJSON,
+}
@@ -0,0 +1,55 @@
+//go:build tinygo || gofluentlite
+
+package validator
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The lite profile leaves out the CLDR tables of golang.org/x/text: locales are not validated,
+// plural choices know the rules of English and of the bundled Arabic messages, and parameters are
+// not localized.
+
+// baseLanguage returns the language of locale ex: ar for ar-EG
+func baseLanguage(locale string) (string, bool) {
+	base, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	base = strings.ToLower(base)
+	return base, base != ""
+}
+
+// pluralForm returns the plural category of the count param holds in locale, with the Arabic rules
+// for Arabic and the English ones otherwise, other when param is not an integer
+func pluralForm(locale, param string) string {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return otherForm
+	}
+	if n < 0 {
+		n = -n
+	}
+	if base, _ := baseLanguage(locale); base == arabicLocale {
+		switch rem := n % 100; {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case rem >= 3 && rem <= 10:
+			return "few"
+		case rem >= 11:
+			return "many"
+		}
+		return otherForm
+	}
+	if n == 1 {
+		return "one"
+	}
+	return otherForm
+}
+
+// formatParam returns param unchanged, numbers and dates are not localized in the lite profile
+func formatParam(_, param string) string {
+	return param
+}
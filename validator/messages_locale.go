@@ -0,0 +1,87 @@
+//go:build !tinygo && !gofluentlite
+
+package validator
+
+import (
+	"strconv"
+	"time"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// pluralForms names the CLDR plural categories usable in a plural choice
+var pluralForms = map[plural.Form]string{
+	plural.Zero: "zero", plural.One: "one", plural.Two: "two", plural.Few: "few", plural.Many: "many", plural.Other: otherForm,
+}
+
+// baseLanguage returns the language of locale ex: ar for ar-EG, ok is false for invalid locales
+func baseLanguage(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+	base, _ := tag.Base()
+	return base.String(), true
+}
+
+// pluralForm returns the CLDR plural category of the count param holds in locale, other when
+// param is not an integer
+func pluralForm(locale, param string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return otherForm
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return otherForm
+	}
+	if n < 0 {
+		n = -n
+	}
+	return pluralForms[plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)]
+}
+
+// paramDateLayouts are the layouts date parameters are read from
+var paramDateLayouts = []string{time.RFC3339, time.DateOnly}
+
+// formatParam renders a rule parameter the way locale writes it: numbers with the locale separators
+// ex: 1.234,5 for de, and dates in the locale date order ex: 31/01/2024 for fr.
+// Other parameters, and parameters of unknown locales, are returned unchanged.
+func formatParam(locale, param string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return param
+	}
+
+	if n, err := strconv.ParseInt(param, 10, 64); err == nil {
+		return message.NewPrinter(tag).Sprint(n)
+	}
+	if f, err := strconv.ParseFloat(param, 64); err == nil {
+		return message.NewPrinter(tag).Sprint(f)
+	}
+	for _, layout := range paramDateLayouts {
+		if t, err := time.Parse(layout, param); err == nil {
+			return t.Format(dateLayout(tag))
+		}
+	}
+	return param
+}
+
+// dateLayout returns the numeric date layout of a language, day first unless the language or region
+// writes the month or the year first
+func dateLayout(tag language.Tag) string {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+
+	switch {
+	case region.String() == "US" || region.String() == "PH":
+		return "01/02/2006"
+	case base.String() == "ja" || base.String() == "zh" || base.String() == "ko" || base.String() == "hu":
+		return "2006/01/02"
+	case base.String() == "de" || base.String() == "ru" || base.String() == "pl" || base.String() == "tr":
+		return "02.01.2006"
+	}
+	return "02/01/2006"
+}
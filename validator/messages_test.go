@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type requiredIfMessage struct {
+	Country string
+	State   string `validate:"required_if=Country US"`
+	Zip     string `validate:"required_if_any=Country US Country CA"`
+}
+
+type uniqueWithMessage struct {
+	OldPassword string
+	NewPassword string `validate:"unique_with=OldPassword"`
+}
+
+func TestBuiltInMessages(t *testing.T) {
+	past := time.Now().Add(-2 * time.Hour)
+	future := time.Now().Add(2 * time.Hour)
+	tests := []struct {
+		// key is the key of the template in defaultMessages
+		key   string
+		value interface{}
+		tag   string
+		setup func(v *Validator)
+		want  string
+	}{
+		{key: CodeRequired, value: "", tag: "required", want: "field is required"},
+		{key: CodeNotBlank, value: "  ", tag: "notblank", want: "field must not be blank"},
+		{key: CodeMustBeDefault, value: 1, tag: "isdefault", want: "field must not be set"},
+		{key: CodeMinLength, value: "a", tag: "min=2", want: "length must be at least 2"},
+		{key: CodeMinValue, value: 1, tag: "min=18", want: "value must be at least 18"},
+		{key: CodeMinItems, value: []int{}, tag: "min=1", want: "must contain at least 1 item"},
+		{key: CodeMaxLength, value: "abc", tag: "max=2", want: "length must be at most 2"},
+		{key: CodeMaxValue, value: 5, tag: "max=3", want: "value must be at most 3"},
+		{key: CodeMaxItems, value: []int{1, 2, 3}, tag: "max=2", want: "must contain at most 2 items"},
+		{key: CodeRangeLength, value: "a", tag: "range=2:4", want: "length must be between 2 and 4"},
+		{key: CodeRangeValue, value: 0.5, tag: "range=1.5:3", want: "value must be between 1.5 and 3"},
+		{key: CodeRangeItems, value: []int{1}, tag: "range=2:3", want: "must contain between 2 and 3 items"},
+		{key: CodeDigits, value: 12, tag: "digits=3", want: "must have exactly 3 digits"},
+		{key: digitsOnlyMessage, value: "12a", tag: "digits=3", want: "must contain digits only"},
+		{key: CodeDecimal, value: 12.345, tag: "decimal=4:2", want: "must have at most 4 integer digits and 2 decimal places"},
+		{key: numberMessage, value: "1.2.3", tag: "decimal=4:2", want: "must be a number"},
+		{key: CodeEmail, value: "user", tag: "email", want: "invalid email format"},
+		{key: CodeRegex, value: "b", tag: "regex=^a$", want: "value does not match required format"},
+		{key: regexInputMessage, value: "aaaa", tag: "regex=^a+$", setup: func(v *Validator) {
+			v.SetRegexPolicy(RegexPolicy{MaxInputLength: 3})
+		}, want: "must be at most 3 bytes to be matched"},
+		{key: CodeOneOf, value: "d", tag: "oneof=a b c", want: "must be one of: a, b, c"},
+		{key: CodeContains, value: "abc", tag: "contains=x", want: `must contain "x"`},
+		{key: CodeE164, value: "0100", tag: "e164", want: "invalid E.164 phone number"},
+		{key: CodeJSON, value: "{", tag: "json", want: "must be valid JSON"},
+		{key: CodeNotBeforeNow, value: past, tag: "gte_now", want: "must not be in the past"},
+		{key: CodeNotAfterNow, value: future, tag: "lte_now", want: "must not be in the future"},
+		{key: CodeWithin, value: past, tag: "within=1h", want: "must be within 1h0m0s of now"},
+		{key: timeMessage, value: "yesterday", tag: "gte_now", want: "must be an RFC3339 time"},
+		{key: negatedCodePrefix + "REQUIRED", value: "a", tag: "!required", want: "field must be empty"},
+		{key: negatedCodePrefix + "NOT_BLANK", value: "a", tag: "!notblank", want: "field must be blank"},
+		{key: negatedCodePrefix + "MUST_BE_DEFAULT", value: 0, tag: "!isdefault", want: "field must be set"},
+		{key: negatedCodePrefix + "EMAIL", value: "a@b.io", tag: "!email", want: "must not be an email address"},
+		{key: negatedCodePrefix + "PATTERN", value: "a", tag: "!regex=^a$", want: "value must not match the format"},
+		{key: negatedCodePrefix + "ONE_OF", value: "a", tag: "!oneof=a b", want: "must not be one of: a, b"},
+		{key: negatedCodePrefix + "CONTAINS", value: "abc", tag: "!contains=b", want: `must not contain "b"`},
+		{key: negatedCodePrefix + "JSON", value: "{}", tag: "!json", want: "must not be JSON"},
+		{key: negatedRuleMessage, value: "abc", tag: "!min=2", want: "must not satisfy min=2"},
+	}
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			v := New()
+			if tt.setup != nil {
+				tt.setup(v)
+			}
+			checkMessage(t, v.Check(tt.value, tt.tag), tt.want)
+		})
+		covered[tt.key] = true
+	}
+
+	structTests := []struct {
+		key   string
+		value interface{}
+		want  string
+	}{
+		{key: CodeRequiredIf, value: &requiredIfMessage{Country: "US", Zip: "1"}, want: "field is required when Country is US"},
+		{key: CodeRequiredIfAny, value: &requiredIfMessage{Country: "CA", State: "ON"}, want: "field is required when Country is US or Country is CA"},
+		{key: CodeUniqueWith, value: &uniqueWithMessage{OldPassword: "secret", NewPassword: "secret"}, want: "must differ from OldPassword"},
+	}
+	for _, tt := range structTests {
+		t.Run(tt.key, func(t *testing.T) {
+			checkMessage(t, New().Validate(tt.value), tt.want)
+		})
+		covered[tt.key] = true
+	}
+
+	for key := range defaultMessages {
+		if !covered[key] {
+			t.Errorf("the message %s has no test case", key)
+		}
+	}
+}
+
+// checkMessage checks that err holds a single validation error reported with want
+func checkMessage(t *testing.T, err error, want string) {
+	t.Helper()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("got error %v, want one validation error", err)
+	}
+	if errs[0].Message != want {
+		t.Errorf("got message %q, want %q", errs[0].Message, want)
+	}
+}
+
+func TestRegionalLocalesUseLanguageMessages(t *testing.T) {
+	v := New()
+	v.RegisterMessage("ar", "required", "{field} مطلوب جدًا")
+	v.RegisterFieldTranslation("Email", "ar", "البريد الإلكتروني")
+	err := v.ValidateContext(context.Background(), &struct {
+		Email string `validate:"required"`
+	}{}, WithLocale("ar-EG"))
+	checkMessage(t, err, "البريد الإلكتروني مطلوب جدًا")
+}
+
+func TestBundledArabicMessagesNameTheField(t *testing.T) {
+	v := New()
+	v.SetLocale("ar")
+	v.RegisterFieldTranslation("Email", "ar", "البريد الإلكتروني")
+	err := v.Validate(&struct {
+		Email string `validate:"required"`
+	}{})
+	checkMessage(t, err, isolate("البريد الإلكتروني")+" مطلوب")
+	// values checked without a field name are called الحقل
+	checkMessage(t, v.Check("", "required"), isolate("الحقل")+" مطلوب")
+}
+
+func isolate(s string) string {
+	return firstStrongIsolate + s + popDirectionIsolate
+}
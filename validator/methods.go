@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"unicode"
+)
+
+// methodTag names the struct tag attaching rules to methods of the struct, the method name and its
+// rules separated by a colon ex: `validate_method:"FullName:required,min=5"`
+const methodTag = "validate_method"
+
+// methodSeparator separates the methods of a validate_method tag ex: "FullName:min=5;Initials:max=3"
+const methodSeparator = ';'
+
+// methodPlan is a method of a struct type validated through a validate_method tag
+type methodPlan struct {
+	// field carries the tag, its groups apply to the method
+	field reflect.StructField
+	name  string
+	rules []string
+	// invalid explains why the method cannot be validated, "" when it can
+	invalid string
+}
+
+// methodPlans reads the validate_method tag of field, typ being the struct type holding it
+func methodPlans(typ reflect.Type, field reflect.StructField) []methodPlan {
+	tag := field.Tag.Get(methodTag)
+	if tag == "" {
+		return nil
+	}
+	var plans []methodPlan
+	for _, entry := range splitUnquoted(tag, methodSeparator) {
+		name, rules, _ := strings.Cut(entry, ":")
+		plan := methodPlan{field: field, name: strings.TrimSpace(name)}
+		if rules = strings.TrimSpace(rules); rules != "" {
+			plan.rules = splitRules(rules)
+		}
+		method, ok := reflect.PointerTo(typ).MethodByName(plan.name)
+		// method expressions take the receiver as their first argument
+		if !ok || method.Type.NumIn() != 1 || method.Type.NumOut() != 1 {
+			plan.invalid = fmt.Sprintf("%s has no method %s taking no argument and returning one value", typ, plan.name)
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// validateMethods applies the rules of the validate_method tags of structVal to the values its
+// methods return, reported under the method name ex: Address.FullName
+func (v *validation) validateMethods(structVal reflect.Value, prefix string, methods []methodPlan, groups []string) error {
+	for _, method := range methods {
+		if !inGroups(method.field, groups) {
+			continue
+		}
+		fieldName := fieldPath(prefix, v.opts.fieldName(method.name))
+		if method.invalid != "" {
+			return &InvalidRuleError{Field: fieldName, Rule: strings.Join(method.rules, ","), Reason: method.invalid}
+		}
+		// structs reached through unexported fields cannot be called
+		if !structVal.CanInterface() {
+			continue
+		}
+		result := methodReceiver(structVal).MethodByName(method.name).Call(nil)[0]
+		parentCover := v.cover
+		v.cover = coveredField{typ: structVal.Type(), name: method.name}
+		err := v.validateValue(result, structVal, fieldName, method.rules, -1)
+		v.cover = parentCover
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// methodReceiver returns a pointer to structVal, or to a copy of it when it cannot be addressed ex:
+// a map value, so methods with either receiver can be called
+func methodReceiver(structVal reflect.Value) reflect.Value {
+	if structVal.CanAddr() {
+		return structVal.Addr()
+	}
+	copied := reflect.New(structVal.Type())
+	copied.Elem().Set(structVal)
+	return copied
+}
+
+// methodName returns the name of the method fn is an expression of ex: FullName for
+// (*User).FullName, "" for other functions
+func methodName(fn interface{}) string {
+	info := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if info == nil {
+		return ""
+	}
+	name := strings.TrimSuffix(info.Name(), "-fm")
+	name = name[strings.LastIndexByte(name, '.')+1:]
+	// closures are named func1, func2 and so on
+	if strings.HasPrefix(name, "func") && strings.TrimLeftFunc(name[len("func"):], unicode.IsDigit) == "" {
+		return ""
+	}
+	return name
+}
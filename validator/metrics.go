@@ -0,0 +1,48 @@
+package validator
+
+import (
+	"reflect"
+	"time"
+)
+
+// MetricsCollector receives measurements about validations, ex: to export them as Prometheus metrics.
+// structType is the name of the validated type ex: main.User
+type MetricsCollector interface {
+	// ObserveValidation is called once per Validate call with its duration and whether it failed
+	ObserveValidation(structType string, duration time.Duration, failed bool)
+	// ObserveFailure is called once per failed rule
+	ObserveFailure(structType, field, rule string)
+}
+
+// SetMetricsCollector installs collector to observe every Validate call, nil disables metrics
+func (v *Validator) SetMetricsCollector(collector MetricsCollector) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.metrics = collector
+}
+
+func (o *callOptions) observe(s interface{}, start time.Time, err error) {
+	if o.metrics == nil {
+		return
+	}
+
+	structType := typeName(s)
+	o.metrics.ObserveValidation(structType, time.Since(start), err != nil)
+	if errs, ok := err.(ValidationErrors); ok {
+		for _, errVal := range errs {
+			o.metrics.ObserveFailure(structType, errVal.Field, errVal.Rule)
+		}
+	}
+}
+
+// typeName names the type behind s, looking through pointers
+func typeName(s interface{}) string {
+	t := reflect.TypeOf(s)
+	if t == nil {
+		return "nil"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.String()
+}
@@ -0,0 +1,95 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldNameCase selects how struct field names are rendered in errors
+type FieldNameCase int
+
+const (
+	// GoCase keeps the Go field name ex: HomeAddress
+	GoCase FieldNameCase = iota
+	// SnakeCase renders home_address
+	SnakeCase
+	// CamelCase renders homeAddress
+	CamelCase
+	// KebabCase renders home-address
+	KebabCase
+)
+
+// SetFieldNameCase sets the casing of the field names reported in errors, applied to every
+// segment of nested paths ex: home_address.zip_code
+func (v *Validator) SetFieldNameCase(c FieldNameCase) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.fieldNameCase = c
+}
+
+// fieldName renders a struct field name with the configured casing
+func (s *settings) fieldName(name string) string {
+	return convertCase(name, s.fieldNameCase)
+}
+
+// pathName renders every field name of a dotted path with the configured casing ex: home_address.zip_code
+func (s *settings) pathName(path string) string {
+	if s.fieldNameCase == GoCase {
+		return path
+	}
+	names := strings.Split(path, ".")
+	for i, name := range names {
+		names[i] = s.fieldName(name)
+	}
+	return strings.Join(names, ".")
+}
+
+// convertCase renders a Go identifier with the given casing
+func convertCase(name string, c FieldNameCase) string {
+	if c == GoCase {
+		return name
+	}
+
+	words := splitWords(name)
+	switch c {
+	case SnakeCase:
+		return strings.ToLower(strings.Join(words, "_"))
+	case KebabCase:
+		return strings.ToLower(strings.Join(words, "-"))
+	case CamelCase:
+		for i, word := range words {
+			word = strings.ToLower(word)
+			if i > 0 {
+				word = strings.ToUpper(word[:1]) + word[1:]
+			}
+			words[i] = word
+		}
+		return strings.Join(words, "")
+	}
+	return name
+}
+
+// splitWords splits a Go identifier into words, keeping acronyms together ex: UserID -> User ID, HTTPServer -> HTTP Server
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		lowerToUpper := unicode.IsUpper(cur) && !unicode.IsUpper(prev)
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if cur == '_' || lowerToUpper || acronymEnd {
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+			if cur == '_' {
+				start = i + 1
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// groupsTag names the struct tag listing the groups a field belongs to ex: `groups:"create,update"`
+const groupsTag = "groups"
+
+// errFailFast stops walking the struct once the first failure is recorded under WithFailFast
+var errFailFast = errors.New("fail fast")
+
+// Option changes the behavior of a single Validate call, so one shared Validator can serve
+// call sites with different needs ex: v.Validate(&user, WithGroups("create"), WithFailFast())
+type Option func(*callOptions)
+
+// callOptions is the configuration of a single Validate call
+type callOptions struct {
+	groups   []string
+	failFast bool
+	locale   string
+	settings
+}
+
+// WithGroups only validates the fields of the given groups, plus the fields without a groups tag.
+// Without it every field is validated whatever its groups.
+func WithGroups(groups ...string) Option {
+	return func(o *callOptions) {
+		o.groups = append(o.groups, groups...)
+	}
+}
+
+// WithFailFast stops at the first failure, the returned ValidationErrors then holds a single error
+func WithFailFast() Option {
+	return func(o *callOptions) {
+		o.failFast = true
+	}
+}
+
+// WithLocale reports failures with the messages registered for locale by RegisterMessage ex: fr,
+// overriding SetLocale. Rules without a message for the locale keep their default one.
+func WithLocale(locale string) Option {
+	return func(o *callOptions) {
+		o.locale = locale
+	}
+}
+
+// newCallOptions applies opts in order over the defaults and settings of v
+func (v *Validator) newCallOptions(opts []Option) callOptions {
+	v.mu.RLock()
+	o := callOptions{locale: v.locale, settings: v.settings}
+	v.mu.RUnlock()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// GroupsProvider is implemented by structs choosing their active groups at runtime, ex: from a status
+// field, for entities whose required fields depend on their state. The groups apply to the fields of
+// that struct, together with the groups of WithGroups.
+type GroupsProvider interface {
+	ValidationGroups() []string
+}
+
+// structGroups returns the groups active for the fields of structVal
+func (o callOptions) structGroups(structVal reflect.Value) []string {
+	provider, ok := asGroupsProvider(structVal)
+	if !ok {
+		return o.groups
+	}
+	return append(append([]string(nil), o.groups...), provider.ValidationGroups()...)
+}
+
+// asGroupsProvider returns structVal, or its address for pointer receivers, as a GroupsProvider
+func asGroupsProvider(structVal reflect.Value) (GroupsProvider, bool) {
+	if structVal.CanAddr() && structVal.Addr().CanInterface() {
+		if provider, ok := structVal.Addr().Interface().(GroupsProvider); ok {
+			return provider, true
+		}
+	}
+	if !structVal.CanInterface() {
+		return nil, false
+	}
+	if provider, ok := structVal.Interface().(GroupsProvider); ok {
+		return provider, true
+	}
+	// map values cannot be addressed, pointer receivers are called on a copy
+	copied := reflect.New(structVal.Type())
+	copied.Elem().Set(structVal)
+	provider, ok := copied.Interface().(GroupsProvider)
+	return provider, ok
+}
+
+// inGroups reports whether a field is validated under the active groups
+func inGroups(field reflect.StructField, groups []string) bool {
+	tagVal := field.Tag.Get(groupsTag)
+	if len(groups) == 0 || tagVal == "" {
+		return true
+	}
+	for _, group := range strings.Split(tagVal, ",") {
+		for _, active := range groups {
+			if strings.Trim(group, " ") == active {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addError records a failure, translated for the call locale. Under WithFailFast it returns
+// errFailFast so the walk stops.
+func (v *validation) addError(valErr ValidationError, param string) error {
+	valErr.Message = v.message(v.opts.locale, valErr, param)
+	v.errors = append(v.errors, valErr)
+	if v.opts.failFast {
+		return errFailFast
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMalformedRuleParams(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		tag   string
+		rule  string
+	}{
+		{value: "a", tag: "min=abc", rule: min},
+		{value: "a", tag: "max=1.5", rule: max},
+		{value: 2, tag: "range=1", rule: rangeRule},
+		{value: 2, tag: "range=3:1", rule: rangeRule},
+		{value: 12, tag: "digits=0", rule: digits},
+		{value: 1.5, tag: "decimal=8,2", rule: decimal},
+		{value: time.Now(), tag: "within=soon", rule: within},
+		{value: "a", tag: "!min=abc", rule: min},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			err := New().Check(tt.value, tt.tag)
+			var ruleErr *InvalidRuleError
+			if !errors.As(err, &ruleErr) || ruleErr.Rule != tt.rule {
+				t.Errorf("Check() error = %v, want an *InvalidRuleError for %s", err, tt.rule)
+			}
+		})
+	}
+}
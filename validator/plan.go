@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
+)
+
+// fieldPlan is a struct field with its validate tag parsed, see structPlanOf
+type fieldPlan struct {
+	structwalk.Field
+	// tag is the validate tag and rules its rules in tag order, ordered by presenceFirst
+	tag            string
+	rules, ordered []string
+	mask           string
+	// unvalidatable explains why the field is skipped, see unvalidatableField
+	unvalidatable string
+	// validatorRule is the rule of a function registered with RegisterFieldValidator
+	validatorRule string
+	// sanitizers rewrite the field before its rules, from its clean tag
+	sanitizers []string
+}
+
+// structPlan holds the fields of a struct type, parsed once per type
+type structPlan struct {
+	fields []fieldPlan
+	// methods are the methods validated through validate_method tags, see methodPlans
+	methods []methodPlan
+	// flat is set for structs whose fields are all exported scalars, whose rules apply to the
+	// field alone: validateFields applies them as parsed, without walking the fields for nested
+	// structs or elements
+	flat bool
+}
+
+// structPlans holds the *structPlan of every struct type validated
+var structPlans sync.Map
+
+// structPlanOf returns the plan of the struct type typ, parsing its tags on first use. Plans only
+// hold what the tags say, rules registered on a Validator are looked up on every call.
+func structPlanOf(typ reflect.Type) *structPlan {
+	if plan, ok := structPlans.Load(typ); ok {
+		return plan.(*structPlan)
+	}
+	walked := structwalk.Fields(typ)
+	plan := &structPlan{fields: make([]fieldPlan, len(walked)), flat: true}
+	for i, field := range walked {
+		tag := field.Tag.Get(validate)
+		var rules []string
+		if tag != "" {
+			rules = splitRules(tag)
+		}
+		// the rules of a clean tag come ahead of those of a validate tag
+		var sanitizers []string
+		if clean, ok := field.Tag.Lookup(cleanTag); ok {
+			var cleanRules []string
+			sanitizers, cleanRules = parseCleanTag(clean)
+			rules = append(cleanRules, rules...)
+			if tag == "" {
+				tag = clean
+			}
+		}
+		plan.fields[i] = fieldPlan{
+			Field:         field,
+			tag:           tag,
+			rules:         rules,
+			ordered:       presenceFirst(rules),
+			mask:          FieldMask(field.StructField),
+			unvalidatable: unvalidatableField(field),
+			validatorRule: fieldValidatorRule(field.Name),
+			sanitizers:    sanitizers,
+		}
+		plan.flat = plan.flat && isFlatField(field, rules)
+		plan.methods = append(plan.methods, methodPlans(typ, field.StructField)...)
+	}
+	cached, _ := structPlans.LoadOrStore(typ, plan)
+	return cached.(*structPlan)
+}
+
+// isFlatField reports whether field is an exported scalar whose rules need neither its
+// elements nor the rules around them
+func isFlatField(field structwalk.Field, rules []string) bool {
+	if !field.IsExported() {
+		return false
+	}
+	switch field.Type.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return false
+	}
+	for _, rule := range rules {
+		if name, _ := parseRule(rule); name == dive || name == structOnly || name == skipUnless {
+			return false
+		}
+	}
+	return true
+}
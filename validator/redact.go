@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tags marking sensitive fields, whose values are masked in validation messages and by the
+// serializer ex: `sensitive:"true"` or `mask:"last4"`
+const (
+	sensitiveTag = "sensitive"
+	maskTag      = "mask"
+)
+
+// Masks of the mask tag, lastN and firstN keep N characters ex: last4 for card numbers, every
+// other mask hides the whole value
+const (
+	maskFull  = "full"
+	maskLast  = "last"
+	maskFirst = "first"
+)
+
+// maskText replaces the hidden characters of a masked value, fullMask a whole value so its length
+// is not shown either
+const (
+	maskText = "*"
+	fullMask = "********"
+)
+
+// FieldMask returns the mask of field: the mask tag, full for `sensitive:"true"`, "" for fields
+// that are not sensitive
+func FieldMask(field reflect.StructField) string {
+	if mask, ok := field.Tag.Lookup(maskTag); ok && mask != "" {
+		return mask
+	}
+	if sensitive, _ := strconv.ParseBool(field.Tag.Get(sensitiveTag)); sensitive {
+		return maskFull
+	}
+	return ""
+}
+
+// Mask hides text according to mask: lastN and firstN keep the last or first N characters ex:
+// ************1234 for last4, other masks replace the text with ********. Text no longer than N is
+// hidden entirely, so a short secret is never shown whole. Empty text stays empty.
+func Mask(text, mask string) string {
+	if text == "" {
+		return ""
+	}
+	chars := []rune(text)
+	keep := 0
+	for _, prefix := range []string{maskLast, maskFirst} {
+		if n, err := strconv.Atoi(strings.TrimPrefix(mask, prefix)); strings.HasPrefix(mask, prefix) && err == nil && n > 0 {
+			keep = n
+		}
+	}
+	if keep == 0 || keep >= len(chars) {
+		return fullMask
+	}
+	hidden := strings.Repeat(maskText, len(chars)-keep)
+	if strings.HasPrefix(mask, maskFirst) {
+		return string(chars[:keep]) + hidden
+	}
+	return hidden + string(chars[len(chars)-keep:])
+}
+
+// redactMessage masks the text of a sensitive value where a message quotes it ex: a custom
+// validator returning "4111111111111111 is not a known card". Only whole words are masked, so the
+// value 1 is not masked within "at least 18".
+func redactMessage(msg string, fieldVal reflect.Value, mask string) string {
+	for fieldVal.Kind() == reflect.Pointer || fieldVal.Kind() == reflect.Interface {
+		if fieldVal.IsNil() {
+			return msg
+		}
+		fieldVal = fieldVal.Elem()
+	}
+	if !fieldVal.IsValid() || !fieldVal.CanInterface() {
+		return msg
+	}
+	text := fmt.Sprint(fieldVal.Interface())
+	if text == "" {
+		return msg
+	}
+	var sb strings.Builder
+	written := 0
+	for start := 0; ; {
+		i := strings.Index(msg[start:], text)
+		if i < 0 {
+			break
+		}
+		i += start
+		end := i + len(text)
+		// matches overlapping a masked one are left out ex: ab-ab within ab-ab-ab
+		if i >= written && isWordBoundary(msg[:i], true) && isWordBoundary(msg[end:], false) {
+			sb.WriteString(msg[written:i])
+			sb.WriteString(Mask(text, mask))
+			written, start = end, end
+			continue
+		}
+		start = i + 1
+	}
+	sb.WriteString(msg[written:])
+	return sb.String()
+}
+
+// isWordBoundary reports whether the text before, or after, a match ends a word: it is empty or
+// the adjacent character is neither a letter nor a digit
+func isWordBoundary(text string, before bool) bool {
+	if text == "" {
+		return true
+	}
+	var r rune
+	if before {
+		r, _ = utf8.DecodeLastRuneInString(text)
+	} else {
+		r, _ = utf8.DecodeRuneInString(text)
+	}
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
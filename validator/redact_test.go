@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type redactedSecret struct {
+	Value string `validate:"known" sensitive:"true"`
+}
+
+type redactedCard struct {
+	Value string `validate:"known" mask:"last4"`
+}
+
+func TestSensitiveValuesAreRedacted(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		message string
+		want    string
+	}{
+		{name: "whole value", value: &redactedSecret{Value: "secret"}, message: "secret is not allowed", want: "******** is not allowed"},
+		{name: "whole words only", value: &redactedSecret{Value: "1"}, message: "must be at least 18, got 1", want: "must be at least 18, got ********"},
+		{name: "overlapping matches", value: &redactedSecret{Value: "ab-ab"}, message: "value ab-ab-ab-ab rejected", want: "value ********-******** rejected"},
+		{name: "overlap within a word", value: &redactedSecret{Value: "aa"}, message: "got aaa", want: "got aaa"},
+		{name: "last4", value: &redactedCard{Value: "4111111111111111"}, message: "4111111111111111 is not a known card", want: "************1111 is not a known card"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			v.RegisterCustomValidator("known", func(reflect.Value) error {
+				return errors.New(tt.message)
+			})
+			checkMessage(t, v.Validate(tt.value), tt.want)
+		})
+	}
+}
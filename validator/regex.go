@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Regexp is a compiled pattern of the regex rule, as returned by the Compile of a RegexPolicy
+type Regexp interface {
+	MatchString(s string) bool
+}
+
+// RegexPolicy bounds the patterns of the regex rule and the values they are matched against, for
+// rules loaded from configuration that cannot be trusted. Patterns must be RE2 syntax, even when
+// another engine compiles them, and a pattern outside the bounds fails validation with an
+// *InvalidRuleError. A zero bound is no bound.
+type RegexPolicy struct {
+	// MaxPatternLength is the length in bytes of the longest pattern accepted
+	MaxPatternLength int
+	// MaxComplexity is the number of instructions of the largest compiled pattern accepted, counted
+	// repetitions growing it quickly ex: ^.{1,255}$ takes 513, (\w{1,100}){1,10} over 2000
+	MaxComplexity int
+	// MaxInputLength is the length in bytes of the longest value matched, longer values fail the rule
+	// without being matched
+	MaxInputLength int
+	// Compile compiles the accepted patterns, regexp.Compile when nil
+	Compile func(pattern string) (Regexp, error)
+}
+
+// SandboxedRegexPolicy returns a RegexPolicy suitable for patterns written by users: short
+// patterns, a few thousand instructions and values up to 4 KiB
+func SandboxedRegexPolicy() RegexPolicy {
+	return RegexPolicy{MaxPatternLength: 512, MaxComplexity: 2000, MaxInputLength: 4096}
+}
+
+// SetRegexPolicy applies policy to the patterns of the regex rule. The built in patterns of rules
+// such as email are trusted and left alone.
+func (v *Validator) SetRegexPolicy(policy RegexPolicy) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.regexSandbox = &regexSandbox{policy: policy}
+}
+
+// regexSandbox holds a RegexPolicy and the patterns it checked, shared by clones like the policy
+type regexSandbox struct {
+	policy  RegexPolicy
+	checked sync.Map // pattern to *checkedRegex
+	count   atomic.Int64
+}
+
+// checkedRegex is a pattern compiled under a policy, reason explains why the policy rejected it
+type checkedRegex struct {
+	re     Regexp
+	reason string
+}
+
+// regexInputError is the failure of a value longer than the MaxInputLength of the policy, kept a
+// failure under ! negation since the value was never matched
+type regexInputError struct {
+	max int
+}
+
+func (e *regexInputError) Error() string {
+	return defaultMessage(regexInputMessage, strconv.Itoa(e.max)).Error()
+}
+
+// matchRegexRule applies the regex rule, under the policy when one is set
+func (v *Validator) matchRegexRule(value, pattern, fieldName string) error {
+	v.mu.RLock()
+	sandbox := v.regexSandbox
+	v.mu.RUnlock()
+	if sandbox == nil {
+		if !v.isMatchedRegex(value, pattern) {
+			return defaultMessage(CodeRegex, "")
+		}
+		return nil
+	}
+	if limit := sandbox.policy.MaxInputLength; limit > 0 && len(value) > limit {
+		return &regexInputError{max: limit}
+	}
+	checked := sandbox.compile(pattern)
+	if checked.reason != "" {
+		return &InvalidRuleError{Field: fieldName, Rule: regex, Reason: checked.reason}
+	}
+	if !checked.re.MatchString(value) {
+		return defaultMessage(CodeRegex, "")
+	}
+	return nil
+}
+
+// compile checks pattern against the policy and compiles it, once per pattern up to
+// maxCachedRegexes patterns
+func (s *regexSandbox) compile(pattern string) *checkedRegex {
+	if checked, ok := s.checked.Load(pattern); ok {
+		return checked.(*checkedRegex)
+	}
+	checked := &checkedRegex{}
+	checked.re, checked.reason = s.check(pattern)
+	if s.count.Add(1) <= maxCachedRegexes {
+		s.checked.Store(pattern, checked)
+	}
+	return checked
+}
+
+// check compiles pattern, or returns why the policy rejects it
+func (s *regexSandbox) check(pattern string) (Regexp, string) {
+	policy := s.policy
+	if policy.MaxPatternLength > 0 && len(pattern) > policy.MaxPatternLength {
+		return nil, fmt.Sprintf("pattern is longer than %d bytes", policy.MaxPatternLength)
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Sprintf("pattern is not valid RE2 syntax: %v", err)
+	}
+	if policy.MaxComplexity > 0 {
+		prog, err := syntax.Compile(parsed.Simplify())
+		if err != nil {
+			return nil, fmt.Sprintf("pattern cannot be compiled: %v", err)
+		}
+		if len(prog.Inst) > policy.MaxComplexity {
+			return nil, fmt.Sprintf("pattern compiles to %d instructions, more than %d", len(prog.Inst), policy.MaxComplexity)
+		}
+	}
+	if policy.Compile == nil {
+		re, err := compiledRegex(pattern)
+		if err != nil {
+			return nil, fmt.Sprintf("pattern cannot be compiled: %v", err)
+		}
+		return re, ""
+	}
+	re, err := policy.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Sprintf("pattern cannot be compiled: %v", err)
+	}
+	return re, ""
+}
@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProblemContentType is the media type to send a Problem with
+const ProblemContentType = "application/problem+json"
+
+// Problem is a validation failure as problem details for HTTP APIs, RFC 7807. Marshal it with
+// encoding/json, the field failures are the errors extension member.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Errors   []ProblemError `json:"errors"`
+}
+
+// ProblemError is a field failure of a Problem
+type ProblemError struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// Problem renders the errors as problem details with status 422 Unprocessable Entity. Type, Title,
+// Status and Instance can be changed on the returned value ex: to link the documentation.
+func (ve ValidationErrors) Problem() Problem {
+	problem := Problem{
+		Type:   "about:blank",
+		Title:  "Validation failed",
+		Status: 422,
+		Detail: fmt.Sprintf("%d validation error(s)", len(ve)),
+		Errors: make([]ProblemError, 0, len(ve)),
+	}
+	for _, errVal := range ve {
+		problem.Errors = append(problem.Errors, ProblemError{Field: errVal.Field, Rule: errVal.Rule, Code: errVal.Code, Message: errVal.Message})
+	}
+	return problem
+}
+
+// BulletList renders the errors as plain text, one "- Field: message" line each
+func (ve ValidationErrors) BulletList() string {
+	var sb strings.Builder
+	for _, errVal := range ve {
+		sb.WriteString("- ")
+		// errors of values checked without a struct have no field
+		if errVal.Field != "" {
+			sb.WriteString(errVal.Field)
+			sb.WriteString(": ")
+		}
+		sb.WriteString(errVal.Message)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// FieldMessages returns the messages of each field in rule order, keyed by the field path
+// ex: Items[2].Name, so a form can be rendered again with the messages under its inputs
+func (ve ValidationErrors) FieldMessages() map[string][]string {
+	messages := make(map[string][]string, len(ve))
+	for _, errVal := range ve {
+		messages[errVal.Field] = append(messages[errVal.Field], errVal.Message)
+	}
+	return messages
+}
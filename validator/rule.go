@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// Rule is a reusable validation rule, letting packages ship rule libraries ex: a fintech rules module.
+// Name is the tag the rule answers to, param is the text after = in the tag ex: DE for iban=DE.
+// Returning an *InvalidRuleError reports a mistake in the tag rather than invalid input.
+type Rule interface {
+	Name() string
+	Validate(ctx context.Context, field reflect.Value, param string) error
+}
+
+// RegisterRule registers r under r.Name(), replacing any Rule registered under the same name.
+// Custom validators registered for the same tag take precedence.
+func (v *Validator) RegisterRule(r Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[r.Name()] = r
+}
+
+// RegisterRules registers every rule of a rule library
+func (v *Validator) RegisterRules(rules ...Rule) {
+	for _, r := range rules {
+		v.RegisterRule(r)
+	}
+}
+
+// ruleValidator adapts the Rule named by rule ex: iban=DE to a custom validator bound to ctx
+func (v *Validator) ruleValidator(ctx context.Context, rule string) (CustomValidatorWithParentFunc, bool) {
+	name, param := parseRule(rule)
+
+	v.mu.RLock()
+	r, ok := v.rules[name]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return func(field reflect.Value, _ reflect.Value) error {
+		return r.Validate(ctx, field, param)
+	}, true
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+type strictInner struct {
+	City string `validate:"required"`
+}
+
+type strictUnexported struct {
+	name string `validate:"required"`
+}
+
+type strictFunc struct {
+	Hook func() `validate:"required"`
+}
+
+type strictChan struct {
+	Events chan int `validate:"required"`
+}
+
+type strictUnsafe struct {
+	Raw unsafe.Pointer `validate:"required"`
+}
+
+type strictUntagged struct {
+	Name   string `validate:"required"`
+	hidden string
+	Hook   func()
+	Events chan int
+}
+
+type strictEmbedded struct {
+	strictInner
+}
+
+func TestUnvalidatableFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  interface{}
+		field  string
+		reason string
+	}{
+		{name: "unexported", value: &strictUnexported{}, field: "name", reason: "unexported fields are not validated"},
+		{name: "func", value: &strictFunc{}, field: "Hook", reason: "func fields are not validated"},
+		{name: "chan", value: &strictChan{}, field: "Events", reason: "chan fields are not validated"},
+		{name: "unsafe pointer", value: &strictUnsafe{}, field: "Raw", reason: "unsafe.Pointer fields are not validated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := New().Validate(tt.value); err != nil {
+				t.Errorf("Validate() error = %v, want the field skipped", err)
+			}
+
+			v := New()
+			v.SetStrictMode(true)
+			err := v.Validate(tt.value)
+			var ruleErr *InvalidRuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("strict Validate() error = %v, want an *InvalidRuleError", err)
+			}
+			if ruleErr.Field != tt.field || ruleErr.Rule != "required" || ruleErr.Reason != tt.reason {
+				t.Errorf("strict Validate() error = %+v, want field %s, rule required and reason %q", ruleErr, tt.field, tt.reason)
+			}
+		})
+	}
+}
+
+func TestStrictModeIgnoresUntaggedFields(t *testing.T) {
+	v := New()
+	v.SetStrictMode(true)
+	if err := v.Validate(&strictUntagged{Name: "a"}); err != nil {
+		t.Errorf("Validate() error = %v, want untagged fields skipped", err)
+	}
+}
+
+func TestStrictModeWalksUnexportedEmbeddedStructs(t *testing.T) {
+	v := New()
+	v.SetStrictMode(true)
+	err := v.Validate(&strictEmbedded{})
+	var errs ValidationErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "strictInner.City" {
+		t.Errorf("Validate() error = %v, want strictInner.City reported as required", err)
+	}
+}
@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// TimeoutError reports a custom validator or Rule that did not finish within the timeout set with
+// SetRuleTimeout. The field is neither valid nor invalid, so validation stops and the caller decides
+// ex: answering 503 instead of blocking the request. errors.Is matches context.DeadlineExceeded, or
+// context.Canceled when the context of the call ended first.
+type TimeoutError struct {
+	Field   string
+	Rule    string
+	Timeout time.Duration
+	// Err is the error of the context ex: context.DeadlineExceeded
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("rule %q timed out after %s", e.Rule, e.Timeout)
+	}
+	return fmt.Sprintf("rule %q on field %s timed out after %s", e.Rule, e.Field, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// ContextValidatorFunc is a custom validation function receiving the context of the call, bounded by
+// the timeout of its tag, so checks calling other services can give up ex: a uniqueness lookup
+type ContextValidatorFunc func(ctx context.Context, field reflect.Value) error
+
+// contextRule adapts a ContextValidatorFunc to a Rule
+type contextRule struct {
+	name string
+	fn   ContextValidatorFunc
+}
+
+func (r contextRule) Name() string { return r.name }
+
+func (r contextRule) Validate(ctx context.Context, field reflect.Value, _ string) error {
+	return r.fn(ctx, field)
+}
+
+// RegisterContextValidator registers fn for tagVal like a Rule, the context is the one given to
+// ValidateContext, bounded by SetRuleTimeout
+func (v *Validator) RegisterContextValidator(tagVal string, fn ContextValidatorFunc) {
+	v.RegisterRule(contextRule{name: tagVal, fn: fn})
+}
+
+// SetRuleTimeout bounds the time the custom validator or Rule registered for tagVal may take, so one
+// slow external check cannot stall a request. Rules and context validators get a context ending
+// after timeout, other custom validators are abandoned once it passes and left to finish in the
+// background. Either way Validate returns a *TimeoutError. A zero timeout removes the bound.
+func (v *Validator) SetRuleTimeout(tagVal string, timeout time.Duration) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if timeout <= 0 {
+		delete(v.timeouts, tagVal)
+		return
+	}
+	v.timeouts[tagVal] = timeout
+}
+
+// ruleTimeout returns the timeout set for the rule name, ok is false when there is none
+func (v *Validator) ruleTimeout(name string) (time.Duration, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	timeout, ok := v.timeouts[name]
+	return timeout, ok
+}
+
+// callWithin runs check until ctx ends, reporting the end as a *TimeoutError for rule. A panic of
+// check is raised again in the caller.
+func callWithin(ctx context.Context, rule string, timeout time.Duration, check func() error) error {
+	type outcome struct {
+		err       error
+		recovered interface{}
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				done <- outcome{recovered: recovered}
+			}
+		}()
+		done <- outcome{err: check()}
+	}()
+
+	select {
+	case out := <-done:
+		if out.recovered != nil {
+			panic(out.recovered)
+		}
+		// rules honoring the context return its error once it ends
+		if ctx.Err() != nil && errors.Is(out.err, ctx.Err()) {
+			return &TimeoutError{Rule: rule, Timeout: timeout, Err: ctx.Err()}
+		}
+		return out.err
+	case <-ctx.Done():
+		return &TimeoutError{Rule: rule, Timeout: timeout, Err: ctx.Err()}
+	}
+}
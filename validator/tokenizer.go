@@ -0,0 +1,141 @@
+package validator
+
+import "strings"
+
+const (
+	ruleSeparator = ','
+	quote         = '\''
+	escape        = '\\'
+	openBrace     = '{'
+	closeBrace    = '}'
+)
+
+// escapable are the characters a backslash escapes in a tag, other backslashes are kept as written
+// so regex parameters such as \d need no doubling
+const escapable = ",|'\\"
+
+// splitRules splits a validate tag into its rules. Commas inside a parameter wrapped in single quotes
+// or inside braces, or escaped with a backslash, belong to the rule parameter ex: regex='^a{2,3}$',
+// regex=^a{2,3}$ or regex=^a{2\,3}$
+func splitRules(tagVal string) []string {
+	return splitUnquoted(tagVal, ruleSeparator)
+}
+
+// splitUnquoted splits s on sep outside quoted parameters, braces and escapes, keeping quotes and
+// escapes in the parts. Braces are regex repetitions. A quote or a brace left open is taken as
+// written and splits nothing.
+func splitUnquoted(s string, sep byte) []string {
+	for _, mode := range [][2]bool{{true, true}, {false, true}, {true, false}} {
+		if parts, balanced := splitOutside(s, sep, mode[0], mode[1]); balanced {
+			return parts
+		}
+	}
+	parts, _ := splitOutside(s, sep, false, false)
+	return parts
+}
+
+// splitOutside splits s on sep outside escapes, outside braces when braced and outside the
+// parameters opening with a quote when quoting, balanced is false when a brace or a quote is never
+// closed
+func splitOutside(s string, sep byte, braced, quoting bool) (parts []string, balanced bool) {
+	start, quoted, depth := 0, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == escape && i+1 < len(s) && strings.IndexByte(escapable, s[i+1]) >= 0:
+			i++
+		// \{ and \} match braces in regex, they open nothing
+		case c == escape && i+1 < len(s) && (s[i+1] == openBrace || s[i+1] == closeBrace):
+			i++
+		case c == quote && quoted:
+			quoted = false
+		// other quotes are part of the parameter ex: regex=^[a-z']+$
+		case c == quote && quoting && opensParam(s[start:i]):
+			quoted = true
+		case c == openBrace && braced && !quoted:
+			depth++
+		case c == closeBrace && braced && !quoted && depth > 0:
+			depth--
+		case c == sep && !quoted && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:]), depth == 0 && !quoted
+}
+
+// opensParam reports whether a quote following part starts the parameter of its rule ex: regex=
+func opensParam(part string) bool {
+	part = strings.TrimRight(part, " ")
+	return strings.IndexByte(part, '=') == len(part)-1 && part != ""
+}
+
+// unquoteParam removes the escapes of a rule parameter, and the quotes wrapping it whole ex:
+// '^a{2,3}$' becomes ^a{2,3}$. Other quotes are kept ex: ^[a-z']+$.
+func unquoteParam(param string) string {
+	if strings.IndexByte(param, quote) < 0 && strings.IndexByte(param, escape) < 0 {
+		return param
+	}
+
+	end := len(param)
+	start := 0
+	if wrapped(param) {
+		start, end = 1, len(param)-1
+	}
+	var out strings.Builder
+	for i := start; i < end; i++ {
+		switch c := param[i]; {
+		case c == escape && i+1 < end && strings.IndexByte(escapable, param[i+1]) >= 0:
+			i++
+			out.WriteByte(param[i])
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
+
+// wrapped reports whether param is wrapped in quotes, the closing one not escaped
+func wrapped(param string) bool {
+	if len(param) < 2 || param[0] != quote || param[len(param)-1] != quote {
+		return false
+	}
+	escapes := 0
+	for i := len(param) - 2; i > 0 && param[i] == escape; i-- {
+		escapes++
+	}
+	return escapes%2 == 0
+}
+
+// quoteParam escapes a parameter so unquoteParam gives it back unchanged, for rules built in code
+func quoteParam(param string) string {
+	var out strings.Builder
+	for i := 0; i < len(param); i++ {
+		if strings.IndexByte(escapable, param[i]) >= 0 {
+			out.WriteByte(escape)
+		}
+		out.WriteByte(param[i])
+	}
+	return out.String()
+}
+
+// RuleSpec is one rule of a validate tag ex: Name min and Param 3 for min=3
+type RuleSpec struct {
+	Name    string
+	Param   string
+	Negated bool
+}
+
+// ParseTag splits a validate tag into its rules with their unquoted parameters, for tools that
+// mirror the rules elsewhere ex: a form builder rendering maxlength from max=10
+func ParseTag(tagVal string) []RuleSpec {
+	var specs []RuleSpec
+	for _, rule := range splitRules(tagVal) {
+		name, negated := strings.CutPrefix(strings.Trim(rule, " "), negation)
+		if name == "" {
+			continue
+		}
+		name, param := parseRule(name)
+		specs = append(specs, RuleSpec{Name: name, Param: param, Negated: negated})
+	}
+	return specs
+}
@@ -0,0 +1,28 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTagQuotes(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []RuleSpec
+	}{
+		{tag: `regex='^(a|b),c$',max=3`, want: []RuleSpec{{Name: regex, Param: "^(a|b),c$"}, {Name: max, Param: "3"}}},
+		{tag: `contains='it\'s'`, want: []RuleSpec{{Name: contains, Param: "it's"}}},
+		// quotes that do not wrap the parameter are part of it
+		{tag: `regex=^[a-z']+$,min=2`, want: []RuleSpec{{Name: regex, Param: "^[a-z']+$"}, {Name: min, Param: "2"}}},
+		{tag: `contains=',max=3`, want: []RuleSpec{{Name: contains, Param: "'"}, {Name: max, Param: "3"}}},
+		{tag: `oneof='a b' c`, want: []RuleSpec{{Name: oneOf, Param: "'a b' c"}}},
+		{tag: `regex=^a{2,3}$,!min=1`, want: []RuleSpec{{Name: regex, Param: "^a{2,3}$"}, {Name: min, Param: "1", Negated: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := ParseTag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTag() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+//go:build !tinygo && !gofluentlite
+
+package validator
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanName is the name of the span wrapping ValidateContext
+const spanName = "validator.Validate"
+
+// validatorTracer is the type of the tracer of a Validator, see tracing_lite.go for the lite profile
+type validatorTracer = trace.Tracer
+
+// SetTracer makes ValidateContext run inside a span started from tracer, carrying the struct type,
+// the error count and the failed rules as attributes. A nil tracer disables tracing.
+func (v *Validator) SetTracer(tracer trace.Tracer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.tracer = tracer
+}
+
+func (o *callOptions) startSpan(ctx context.Context, s interface{}) (context.Context, trace.Span) {
+	if o.tracer == nil {
+		return ctx, nil
+	}
+	return o.tracer.Start(ctx, spanName, trace.WithAttributes(
+		attribute.String("validator.struct", typeName(s)),
+	))
+}
+
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	defer span.End()
+
+	errs, ok := err.(ValidationErrors)
+	if !ok {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.Int("validator.error_count", 0))
+		return
+	}
+
+	seen := make(map[string]bool)
+	var rules []string
+	for _, errVal := range errs {
+		if !seen[errVal.Rule] {
+			seen[errVal.Rule] = true
+			rules = append(rules, errVal.Rule)
+		}
+	}
+	sort.Strings(rules)
+
+	span.SetAttributes(
+		attribute.Int("validator.error_count", len(errs)),
+		attribute.StringSlice("validator.failed_rules", rules),
+	)
+	span.SetStatus(codes.Error, "validation failed")
+}
@@ -1,28 +1,141 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/khaledibrahim1015/goFluentValidation.git/internal/structwalk"
 )
 
 const (
 	validate          = "validate"
 	required          = "required"
-	requiredMsg       = "field is required"
 	min               = "min"
 	max               = "max"
 	email             = "email"
 	regex             = "regex"
+	dive              = "dive"
+	skipField         = "-"
+	structOnly        = "structonly"
+	oneOf             = "oneof"
+	contains          = "contains"
+	negation          = "!"
+	orSeparator       = "|"
+	e164              = "e164"
+	notBlank          = "notblank"
+	isDefault         = "isdefault"
+	rangeRule         = "range"
+	digits            = "digits"
+	decimal           = "decimal"
+	jsonRule          = "json"
+	gteNow            = "gte_now"
+	lteNow            = "lte_now"
+	within            = "within"
+	requiredIf        = "required_if"
+	requiredIfAny     = "required_if_any"
+	skipUnless        = "skip_unless"
+	omitEmpty         = "omitempty"
+	fieldValidator    = "field_validator"
+	uniqueWith        = "unique_with"
+	e164RegexPattern  = `^\+[1-9][0-9]{1,14}$`
 	emailRegexPattern = `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 )
 
+// integerKinds are the signed and unsigned integer kinds
+var integerKinds = []reflect.Kind{
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+}
+
+// sizedKinds are the kinds min and max know how to measure
+var sizedKinds = append([]reflect.Kind{
+	reflect.String,
+	reflect.Float32, reflect.Float64,
+	reflect.Slice, reflect.Array, reflect.Map,
+}, integerKinds...)
+
+// ruleKinds lists the field kinds each built-in rule can be applied to.
+// Rules missing from the map accept fields of any kind.
+var ruleKinds = map[string][]reflect.Kind{
+	min:        sizedKinds,
+	max:        sizedKinds,
+	rangeRule:  sizedKinds,
+	digits:     append([]reflect.Kind{reflect.String}, integerKinds...),
+	decimal:    append([]reflect.Kind{reflect.String, reflect.Float32, reflect.Float64}, integerKinds...),
+	email:      {reflect.String},
+	regex:      {reflect.String},
+	oneOf:      append([]reflect.Kind{reflect.String}, integerKinds...),
+	contains:   {reflect.String},
+	e164:       {reflect.String},
+	notBlank:   {reflect.String},
+	jsonRule:   {reflect.String},
+	gteNow:     {reflect.Struct, reflect.String},
+	lteNow:     {reflect.Struct, reflect.String},
+	within:     {reflect.Struct, reflect.String},
+	dive:       {reflect.Slice, reflect.Array, reflect.Map},
+	structOnly: {reflect.Struct, reflect.Pointer, reflect.Map},
+}
+
+// builtinRules are the rule names understood by applyValidationRule
+var builtinRules = map[string]bool{
+	required: true, isDefault: true, min: true, max: true, rangeRule: true, digits: true, decimal: true, email: true, regex: true,
+	oneOf: true, contains: true, e164: true, notBlank: true, jsonRule: true, gteNow: true, lteNow: true, within: true,
+	requiredIf: true, requiredIfAny: true, skipUnless: true, omitEmpty: true, uniqueWith: true, dive: true, structOnly: true,
+}
+
+// timeRules are the rules reading a time.Time or an RFC3339 string
+var timeRules = map[string]bool{gteNow: true, lteNow: true, within: true}
+
+// timeType is the reflect.Type of time.Time
+var timeType = reflect.TypeOf(time.Time{})
+
+// bytesAsStringRules are the rules that read a []byte field as a string
+var bytesAsStringRules = map[string]bool{
+	min: true, max: true, rangeRule: true, digits: true, decimal: true, email: true, regex: true,
+	oneOf: true, contains: true, e164: true, notBlank: true, jsonRule: true,
+}
+
+// errUnknownRule is returned by applyValidationRule for rules that are not built in, custom validators handle them
+var errUnknownRule = errors.New("unknown rule")
+
+// ErrNilValue is returned when the value to validate is nil or a typed nil pointer ex: v.Validate((*User)(nil)).
+// Check the returned error with errors.Is, the message names the offending type.
+var ErrNilValue = errors.New("validator: nil value")
+
+// ErrNilValidator is returned when a method is called on a nil *Validator
+var ErrNilValidator = errors.New("validator: nil *Validator")
+
 // ValidationError represents a single validation error
 type ValidationError struct {
 	Field   string
 	Message string
+	// Rule is the name of the rule that failed ex: min
+	Rule string
+	// Code identifies the failure for clients ex: VAL_MIN_LENGTH, see the Code constants
+	Code string
+
+	// index of the failing element when the error comes from a dive rule
+	index  int
+	isElem bool
+}
+
+// Index returns the slice index of the failing element, or -1 when the error is not about an element
+func (e ValidationError) Index() int {
+	if !e.isElem {
+		return -1
+	}
+	return e.index
 }
 
 // Custom DataTypes
@@ -32,40 +145,370 @@ type (
 
 	// CustomValidatorFunc is a type for custom validation functions
 	CustomValidatorFunc func(field reflect.Value) error
+
+	// CustomValidatorWithParentFunc is a custom validation function that also receives the struct holding the field
+	CustomValidatorWithParentFunc func(field reflect.Value, parent reflect.Value) error
 )
 
+// InvalidRuleError reports a rule applied to a field whose kind it does not support.
+// It signals a mistake in the struct tags rather than invalid input.
+type InvalidRuleError struct {
+	Field string
+	Rule  string
+	Kind  reflect.Kind
+	// Reason describes what is wrong when the problem is not the field kind ex: an unknown field in the parameter
+	Reason string
+}
+
+func (e *InvalidRuleError) Error() string {
+	// values checked without a struct have no field
+	if e.Field == "" {
+		if e.Reason != "" {
+			return fmt.Sprintf("rule %q: %s", e.Rule, e.Reason)
+		}
+		return fmt.Sprintf("rule %q cannot be applied to a value of kind %s", e.Rule, e.Kind)
+	}
+	if e.Reason != "" {
+		return fmt.Sprintf("rule %q on field %s: %s", e.Rule, e.Field, e.Reason)
+	}
+	return fmt.Sprintf("rule %q cannot be applied to field %s of kind %s", e.Rule, e.Field, e.Kind)
+}
+
 func (ve ValidationErrors) Error() string {
 
 	var errMsgs []string
 	for _, errVal := range ve {
+		// errors of values checked without a struct have no field
+		if errVal.Field == "" {
+			errMsgs = append(errMsgs, errVal.Message)
+			continue
+		}
 		errMsgs = append(errMsgs, fmt.Sprintf("%s : %s", errVal.Field, errVal.Message))
 	}
 	return strings.Join(errMsgs, "; ")
 
 }
 
+// ForField returns the errors reported for field, including the failures of its elements and nested fields
+func (ve ValidationErrors) ForField(field string) ValidationErrors {
+	var out ValidationErrors
+	for _, errVal := range ve {
+		if errVal.Field == field || strings.HasPrefix(errVal.Field, field+"[") || strings.HasPrefix(errVal.Field, field+".") {
+			out = append(out, errVal)
+		}
+	}
+	return out
+}
+
+// Deduplicate returns the errors without repeated field and rule pairs, keeping the first occurrence
+func (ve ValidationErrors) Deduplicate() ValidationErrors {
+	type fieldRule struct{ field, rule string }
+	seen := make(map[fieldRule]bool, len(ve))
+
+	var out ValidationErrors
+	for _, errVal := range ve {
+		key := fieldRule{field: errVal.Field, rule: errVal.Rule}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, errVal)
+	}
+	return out
+}
+
+// FirstPerField returns the first error of each field, in rule order, since forms usually show a single
+// message under each input. Elements and nested fields count as fields of their own ex: Items[2]
+func (ve ValidationErrors) FirstPerField() ValidationErrors {
+	seen := make(map[string]bool, len(ve))
+
+	var out ValidationErrors
+	for _, errVal := range ve {
+		if seen[errVal.Field] {
+			continue
+		}
+		seen[errVal.Field] = true
+		out = append(out, errVal)
+	}
+	return out
+}
+
 // Validator handles validation logic
+// A Validator can be shared by goroutines once configured, Validate keeps its state per call.
 type Validator struct {
-	errors           ValidationErrors
-	customValidators map[string]CustomValidatorFunc
+	mu               sync.RWMutex // guards the custom validator registries and the settings
+	customValidators map[string]CustomValidatorWithParentFunc
+	scopedValidators map[scopedKey]CustomValidatorWithParentFunc
+	scopedTags       map[string]bool
+	rules            map[string]Rule
+	messages         map[string]map[string]string // locale to rule to message template
+	labels           map[string]map[string]string // locale to field to label, see RegisterFieldTranslation
+	timeouts         map[string]time.Duration     // tag to timeout of its custom validator, see SetRuleTimeout
+	sanitizers       map[string]SanitizerFunc     // name to sanitizer of the clean tag, see RegisterSanitizer
+	interfaceRules   []interfaceRules
+	cache            *resultCache
+	settings
+	locale string // default locale of the messages, see SetLocale
+}
+
+// settings are the hooks and options set with the On and Set methods under mu. Each call works on
+// the copy taken by newCallOptions, so they can be changed while other goroutines validate.
+type settings struct {
+	preHooks      []PreValidateFunc
+	beforeHooks   []BeforeValidateFunc
+	afterHooks    []AfterValidateFunc
+	metrics       MetricsCollector
+	logger        Logger
+	logLevel      slog.Level
+	tracer        validatorTracer
+	fieldNameCase FieldNameCase
+	strict        bool
+	regexSandbox  *regexSandbox
+	coverage      *Coverage
 }
 
 // New Create a new Validator instance
 
 func New() *Validator {
 	return &Validator{
-		customValidators: make(map[string]CustomValidatorFunc),
+		customValidators: make(map[string]CustomValidatorWithParentFunc),
+		scopedValidators: make(map[scopedKey]CustomValidatorWithParentFunc),
+		scopedTags:       make(map[string]bool),
+		rules:            make(map[string]Rule),
+		messages:         make(map[string]map[string]string),
+		labels:           make(map[string]map[string]string),
+		timeouts:         make(map[string]time.Duration),
+		sanitizers:       make(map[string]SanitizerFunc),
 	}
 }
 
+// Clone returns a new Validator with the same custom validators, hooks and options.
+// A result cache is not shared, the clone starts with an empty one.
+// Registrations made on the clone do not affect v, so request scoped validators can be derived from a shared base.
+func (v *Validator) Clone() *Validator {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	clone := &Validator{
+		customValidators: make(map[string]CustomValidatorWithParentFunc, len(v.customValidators)),
+		scopedValidators: make(map[scopedKey]CustomValidatorWithParentFunc, len(v.scopedValidators)),
+		scopedTags:       make(map[string]bool, len(v.scopedTags)),
+		rules:            make(map[string]Rule, len(v.rules)),
+		messages:         make(map[string]map[string]string, len(v.messages)),
+		labels:           make(map[string]map[string]string, len(v.labels)),
+		timeouts:         make(map[string]time.Duration, len(v.timeouts)),
+		sanitizers:       make(map[string]SanitizerFunc, len(v.sanitizers)),
+		interfaceRules:   append([]interfaceRules(nil), v.interfaceRules...),
+		settings:         v.settings,
+		locale:           v.locale,
+	}
+	// hooks registered on the clone must not land in the arrays of v
+	clone.preHooks = append([]PreValidateFunc(nil), v.preHooks...)
+	clone.beforeHooks = append([]BeforeValidateFunc(nil), v.beforeHooks...)
+	clone.afterHooks = append([]AfterValidateFunc(nil), v.afterHooks...)
+	if v.cache != nil {
+		clone.cache = &resultCache{key: v.cache.key, maxEntries: v.cache.maxEntries, results: make(map[cacheKey]error)}
+	}
+	for tagVal, fn := range v.customValidators {
+		clone.customValidators[tagVal] = fn
+	}
+	for key, fn := range v.scopedValidators {
+		clone.scopedValidators[key] = fn
+	}
+	for tagVal := range v.scopedTags {
+		clone.scopedTags[tagVal] = true
+	}
+	for name, rule := range v.rules {
+		clone.rules[name] = rule
+	}
+	for locale, templates := range v.messages {
+		clone.messages[locale] = make(map[string]string, len(templates))
+		for rule, template := range templates {
+			clone.messages[locale][rule] = template
+		}
+	}
+	for tagVal, timeout := range v.timeouts {
+		clone.timeouts[tagVal] = timeout
+	}
+	for name, fn := range v.sanitizers {
+		clone.sanitizers[name] = fn
+	}
+	for locale, labels := range v.labels {
+		clone.labels[locale] = make(map[string]string, len(labels))
+		for field, label := range labels {
+			clone.labels[locale][field] = label
+		}
+	}
+	return clone
+}
+
 // RegisterCustomValidator registers a custom validation function
 func (v *Validator) RegisterCustomValidator(tagVal string, fn CustomValidatorFunc) {
+	v.RegisterCustomValidatorWithParent(tagVal, func(field reflect.Value, _ reflect.Value) error {
+		return fn(field)
+	})
+}
+
+// RegisterCustomValidatorWithParent registers a custom validation function that can read the other fields
+// of the enclosing struct. For dived elements the parent is the struct holding the slice.
+func (v *Validator) RegisterCustomValidatorWithParent(tagVal string, fn CustomValidatorWithParentFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
 	v.customValidators[tagVal] = fn
 }
 
-// Validate performs basic validation on the provided struct
-func (v *Validator) Validate(s interface{}) error {
-	v.errors = ValidationErrors{}
+// RegisterScopedValidator registers a custom validation function that only applies within scope, given as a
+// value or pointer of the type ex: User{}. The tag then applies to fields of structs of that type, and to fields
+// of that type. Scoped registrations take precedence over the global one, so two teams can both define
+// valid_code in a shared Validator.
+func (v *Validator) RegisterScopedValidator(scope interface{}, tagVal string, fn CustomValidatorWithParentFunc) {
+	scopeType := reflect.TypeOf(scope)
+	if scopeType.Kind() == reflect.Pointer {
+		scopeType = scopeType.Elem()
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.scopedValidators[scopedKey{scope: scopeType, tagVal: tagVal}] = fn
+	v.scopedTags[tagVal] = true
+}
+
+// RegisterFieldValidator registers a custom validation function for one field of a struct type, given as a
+// value or pointer ex: RegisterFieldValidator(User{}, "Email", fn), without any tag on the struct. It suits
+// structs from generated packages. The function runs ahead of the field's own rules and failures are reported
+// with the field_validator rule. Registering again for the same field replaces the function.
+func (v *Validator) RegisterFieldValidator(structType interface{}, fieldName string, fn CustomValidatorWithParentFunc) {
+	v.RegisterScopedValidator(structType, fieldValidatorRule(fieldName), fn)
+}
+
+// fieldValidatorRule is the rule running the function registered for fieldName with RegisterFieldValidator
+func fieldValidatorRule(fieldName string) string {
+	return fieldValidator + "=" + fieldName
+}
+
+// hasFieldValidator reports whether a function is registered for the field of structType, given
+// by its fieldValidatorRule
+func (v *Validator) hasFieldValidator(structType reflect.Type, rule string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.scopedValidators[scopedKey{scope: structType, tagVal: rule}]
+	return ok
+}
+
+// scopedKey identifies a custom validator registered for one type
+type scopedKey struct {
+	scope  reflect.Type
+	tagVal string
+}
+
+// customValidator looks up the validator for tagVal, preferring the one scoped to the enclosing
+// struct, then the one scoped to the field type, then the global one
+func (v *Validator) customValidator(tagVal string, field, parent reflect.Value) (CustomValidatorWithParentFunc, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.scopedTags[tagVal] {
+		for _, scope := range []reflect.Value{parent, field} {
+			if !scope.IsValid() {
+				continue
+			}
+			if fn, ok := v.scopedValidators[scopedKey{scope: scope.Type(), tagVal: tagVal}]; ok {
+				return fn, true
+			}
+		}
+	}
+	fn, ok := v.customValidators[tagVal]
+	return fn, ok
+}
+
+// hasCustomValidator reports whether a validator or a Rule is registered for tagVal, in any scope
+func (v *Validator) hasCustomValidator(tagVal string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.customValidators[tagVal]
+	_, isRule := v.rules[tagVal]
+	return ok || isRule || v.scopedTags[tagVal]
+}
+
+// RegisterTypedValidator registers a custom validation function that receives the field already unwrapped as T.
+// Fields whose type is convertible to T are converted, non nil pointers are dereferenced and nil pointers are
+// left to the required rule. Applying the tag to a field of any other type is reported as an *InvalidRuleError.
+// Go methods cannot take type parameters, hence the package level function.
+func RegisterTypedValidator[T any](v *Validator, tagVal string, fn func(value T) error) {
+	target := reflect.TypeOf((*T)(nil)).Elem()
+
+	v.RegisterCustomValidator(tagVal, func(field reflect.Value) error {
+		// unexported fields cannot be handed out as plain values
+		if !field.CanInterface() {
+			return nil
+		}
+		if field.Kind() == reflect.Pointer && !field.Type().ConvertibleTo(target) {
+			if field.IsNil() {
+				return nil
+			}
+			field = field.Elem()
+		}
+		if !field.Type().ConvertibleTo(target) {
+			return &InvalidRuleError{Rule: tagVal, Kind: field.Kind()}
+		}
+		return fn(field.Convert(target).Interface().(T))
+	})
+}
+
+// Validate performs basic validation on the provided struct, opts adjust this call only
+func (v *Validator) Validate(s interface{}, opts ...Option) error {
+	return v.ValidateContext(context.Background(), s, opts...)
+}
+
+// ValidateContext validates the provided struct like Validate, ctx is handed to the logger and tracer
+func (v *Validator) ValidateContext(ctx context.Context, s interface{}, opts ...Option) error {
+	if v == nil {
+		return ErrNilValidator
+	}
+	call := v.newCallOptions(opts)
+	ctx, span := call.startSpan(ctx, s)
+	start := time.Now()
+	err := v.cachedValidate(ctx, s, call)
+	call.observe(s, start, err)
+	call.logFailure(ctx, s, err)
+	endSpan(span, err)
+	return err
+}
+
+// checkNil reports an ErrNilValue for a nil interface or a nil pointer, before reflect dereferences it
+func checkNil(s interface{}) error {
+	if s == nil {
+		return fmt.Errorf("%w: validation requires a struct pointer input", ErrNilValue)
+	}
+	if rVal := reflect.ValueOf(s); rVal.Kind() == reflect.Pointer && rVal.IsNil() {
+		return fmt.Errorf("%w: %T is a nil pointer", ErrNilValue, s)
+	}
+	return nil
+}
+
+// validation holds the state of a single Validate call
+type validation struct {
+	*Validator
+	ctx     context.Context
+	opts    callOptions
+	errors  ValidationErrors
+	visited map[uintptr]bool
+	// mask is the mask of the sensitive field being validated, its nested fields included
+	mask string
+	// cover is the field whose rules are recorded when a Coverage is set
+	cover coveredField
+}
+
+func (v *Validator) validate(ctx context.Context, s interface{}, opts callOptions) error {
+	run := &validation{Validator: v, ctx: ctx, opts: opts, errors: ValidationErrors{}}
+
+	if stop, err := opts.runPreHooks(s); stop {
+		return err
+	}
+
+	if err := checkNil(s); err != nil {
+		return err
+	}
 
 	rVal := reflect.ValueOf(s)
 	// Validate type pointer
@@ -73,172 +516,1046 @@ func (v *Validator) Validate(s interface{}) error {
 		return fmt.Errorf("validation requires a struct pointer input")
 	}
 
-	// Get the type of the struct  ex:Person struct
-	var structVal reflect.Value
-	structVal = rVal.Elem()
-	// Validate type struct
-	if structVal.Kind() != reflect.Struct {
-		return fmt.Errorf("refOut must be a pointer struct !")
+	// Get the structs to validate ex:Person struct, or every element of []Person
+	roots, err := topLevelStructs(rVal.Elem())
+	if err != nil {
+		return err
 	}
 
-	// validateFields validates individual fields of the struct
-	v.validateFields(structVal)
+	opts.runBeforeHooks(s)
 
-	// Second pass: apply custom validators
-	v.applyCustomValidators(structVal)
+	if err := run.walk(roots); err != nil && err != errFailFast {
+		return err
+	}
 
-	if len(v.errors) > 0 {
-		return v.errors
+	errs := opts.runAfterHooks(s, run.errors)
+	if len(errs) > 0 {
+		return errs
 	}
 
 	return nil
 }
 
-func (v *Validator) validateFields(structVal reflect.Value) {
+// walk validates the roots in order
+func (v *validation) walk(roots []validationRoot) error {
+	for _, root := range roots {
+		v.visited = root.visited()
+		if err := v.validateFields(root.structVal, root.prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validationRoot is a struct validated at the top level, prefix names it in errors ex: [2]
+type validationRoot struct {
+	structVal reflect.Value
+	prefix    string
+}
+
+// visited marks the root struct itself as being walked
+func (root validationRoot) visited() map[uintptr]bool {
+	visited := make(map[uintptr]bool)
+	if root.structVal.CanAddr() {
+		visited[root.structVal.Addr().Pointer()] = true
+	}
+	return visited
+}
+
+// topLevelStructs returns the structs held by the value passed to Validate: the struct itself,
+// or the elements of a slice, array or map of structs (or struct pointers, nil ones are skipped)
+func topLevelStructs(val reflect.Value) ([]validationRoot, error) {
+	if val.Kind() == reflect.Struct {
+		return []validationRoot{{structVal: val}}, nil
+	}
+
+	elemType := val.Type()
+	if k := val.Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+		elemType = elemType.Elem()
+	}
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array && val.Kind() != reflect.Map || !structwalk.IsStruct(elemType) {
+		return nil, fmt.Errorf("validation requires a pointer to a struct, or to a slice, array or map of structs")
+	}
+
+	var roots []validationRoot
+	add := func(elem reflect.Value, prefix string) {
+		if structVal, ok := structwalk.Indirect(elem); ok {
+			roots = append(roots, validationRoot{structVal: structVal, prefix: prefix})
+		}
+	}
+
+	if val.Kind() == reflect.Map {
+		for _, key := range sortedMapKeys(val) {
+			add(val.MapIndex(key), mapKeyName(key))
+		}
+		return roots, nil
+	}
+	for i := 0; i < val.Len(); i++ {
+		add(val.Index(i), fmt.Sprintf("[%d]", i))
+	}
+	return roots, nil
+}
+
+// sortedMapKeys returns the keys of a map in a stable order so errors come out deterministic
+func sortedMapKeys(mapVal reflect.Value) []reflect.Value {
+	keys := mapVal.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+// mapKeyName renders a map key for errors ex: ["primary"] or [3]
+func mapKeyName(key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return fmt.Sprintf("[%q]", key.String())
+	}
+	return fmt.Sprintf("[%v]", key.Interface())
+}
+
+// validateFields validates the fields of a struct, prefix is the path of the struct itself ex: Address
+func (v *validation) validateFields(structVal reflect.Value, prefix string) error {
 
 	// get type
 	structType := structVal.Type()
 
-	for i := 0; i < structVal.NumField(); i++ {
-		currentField := structType.Field(i)
-		currentFieldVal := structVal.Field(i)
+	inherited, err := v.inheritedRules(structVal, prefix)
+	if err != nil {
+		return err
+	}
+	groups := v.opts.structGroups(structVal)
+	plan := structPlanOf(structType)
+	if v.opts.coverage != nil {
+		v.opts.coverage.coverType(structType)
+	}
+
+	for _, field := range plan.fields {
+		currentFieldVal := structVal.Field(field.Pos)
 
 		// Get validation rules from struct tag `validate:"required,min=2,max=50"`
-		tagVal := currentField.Tag.Get(validate)
-		if tagVal == "" {
+		// fields without rules are still walked so nested structs get validated
+		if field.tag == skipField || !inGroups(field.StructField, groups) {
+			continue
+		}
+		if field.unvalidatable != "" {
+			if v.opts.strict && field.tag != "" {
+				return &InvalidRuleError{Field: fieldPath(prefix, v.opts.fieldName(field.Name)), Rule: field.tag, Reason: field.unvalidatable}
+			}
+			continue
+		}
+
+		hasFieldValidator := v.hasFieldValidator(structType, field.validatorRule)
+		parentMask, parentCover := v.mask, v.cover
+		if field.mask != "" {
+			v.mask = field.mask
+		}
+		v.cover = coveredField{typ: structType, name: field.Name}
+		fieldName := fieldPath(prefix, v.opts.fieldName(field.Name))
+		if len(field.sanitizers) > 0 {
+			if err := v.sanitize(currentFieldVal, fieldName, field.sanitizers); err != nil {
+				return err
+			}
+		}
+		if plan.flat && inherited == nil && !hasFieldValidator {
+			// scalar fields hold nothing to walk, their rules are applied as parsed
+			_, err = v.applyRules(currentFieldVal, structVal, fieldName, field.ordered, -1)
+		} else {
+			// rules inherited from interfaces and field validators come first so they stay ahead of dive
+			rules := append([]string(nil), inherited[field.Name]...)
+			if hasFieldValidator {
+				rules = append(rules, field.validatorRule)
+			}
+			rules = append(rules, field.rules...)
+			err = v.validateValue(currentFieldVal, structVal, fieldName, rules, -1)
+		}
+		v.mask, v.cover = parentMask, parentCover
+		if err != nil {
+			return err
+		}
+
+	}
+	return v.validateMethods(structVal, prefix, plan.methods, groups)
+
+}
+
+// validateValue applies the rules to a field value in tag order, built-in and custom alike, and the rules
+// after dive to each of its elements. Failures are therefore recorded in declaration order.
+// index is the element position for dived values, -1 otherwise. parent is the struct holding the field.
+func (v *validation) validateValue(currentFieldVal reflect.Value, parent reflect.Value, fieldName string, rules []string, index int) error {
+
+	rules, elemRules, hasDive := splitDive(rules)
+	rules, skipped, err := applySkipUnless(rules, parent, fieldName)
+	if err != nil {
+		return err
+	}
+	rules = presenceFirst(rules)
+	if done, err := v.applyRules(currentFieldVal, parent, fieldName, rules, index); done || err != nil {
+		return err
+	}
+
+	if skipped {
+		return nil
+	}
+	if !hasDive {
+		if hasRule(rules, structOnly) {
+			return nil
+		}
+		if currentFieldVal.Kind() == reflect.Map {
+			return v.validateMapValues(currentFieldVal, fieldName)
+		}
+		nested, ok := v.enterStruct(currentFieldVal)
+		if !ok {
+			return nil
+		}
+		defer v.leaveStruct(currentFieldVal)
+		return v.validateFields(nested, fieldName)
+	}
+	if err := checkRuleKind(dive, currentFieldVal, fieldName); err != nil {
+		return err
+	}
+	if currentFieldVal.Kind() == reflect.Map {
+		// map values are not elements, their errors carry no index
+		for _, key := range sortedMapKeys(currentFieldVal) {
+			if err := v.validateValue(currentFieldVal.MapIndex(key), parent, fieldName+mapKeyName(key), elemRules, -1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := 0; i < currentFieldVal.Len(); i++ {
+		if err := v.validateValue(currentFieldVal.Index(i), parent, elemName(fieldName, i), elemRules, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMapValues validates the structs, or non nil pointers to structs, held by a map field like
+// nested structs, prefixing errors with their key ex: Config["primary"].Host
+func (v *validation) validateMapValues(mapVal reflect.Value, fieldName string) error {
+	if !structwalk.IsStruct(mapVal.Type().Elem()) {
+		return nil
+	}
+	for _, key := range sortedMapKeys(mapVal) {
+		elem := mapVal.MapIndex(key)
+		nested, ok := v.enterStruct(elem)
+		if !ok {
 			continue
 		}
+		err := v.validateFields(nested, fieldName+mapKeyName(key))
+		v.leaveStruct(elem)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		rules := strings.Split(tagVal, ",")
+// applyRules applies rules, ordered by presenceFirst, to a value. It returns true when an absent
+// omitempty value ends its validation, nothing nested in it is checked then.
+func (v *validation) applyRules(currentFieldVal reflect.Value, parent reflect.Value, fieldName string, rules []string, index int) (bool, error) {
+	for _, rule := range rules {
+		// an absent optional field has nothing else to check
+		if strings.Trim(rule, " ") == omitEmpty {
+			if currentFieldVal.IsZero() {
+				return true, nil
+			}
+			continue
+		}
+
+		alternatives, isOr := v.splitAlternatives(rule)
+		// a rule on the wrong kind is a tag mistake, report it instead of guessing
+		if !isOr {
+			if err := checkRuleKind(rule, currentFieldVal, fieldName); err != nil {
+				return false, err
+			}
+		}
+		for _, alternative := range alternatives {
+			if err := checkRuleKind(alternative, currentFieldVal, fieldName); err != nil {
+				return false, err
+			}
+		}
 
-		for _, rule := range rules {
-			if err := v.applyValidationRule(rule, currentFieldVal, currentField.Name); err != nil {
-				v.errors = append(v.errors, ValidationError{
-					Field:   currentField.Name,
-					Message: err.Error(),
-				})
+		var err error
+		if isOr {
+			err = v.applyAlternatives(alternatives, currentFieldVal, parent, fieldName)
+		} else {
+			err = v.applyValidationRule(rule, currentFieldVal, parent, fieldName)
+		}
+		if err == errUnknownRule {
+			err = v.applyCustomRule(rule, currentFieldVal, parent)
+		}
+		if v.opts.coverage != nil && v.cover.typ != nil {
+			v.opts.coverage.coverRule(v.cover, strings.Trim(rule, " "), err != nil && err != errUnknownRule)
+		}
+		if err == nil || err == errUnknownRule {
+			continue
+		}
+		// typed validators report fields of the wrong type as configuration errors
+		var ruleErr *InvalidRuleError
+		if errors.As(err, &ruleErr) {
+			if ruleErr.Field == "" {
+				ruleErr.Field = fieldName
+			}
+			return false, ruleErr
+		}
+		// a check that timed out gave no answer, the field is neither valid nor invalid
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			if timeoutErr.Field == "" {
+				timeoutErr.Field = fieldName
 			}
+			return false, timeoutErr
+		}
+		name, param := parseRule(rule)
+		if err := v.addError(ValidationError{
+			Field:   fieldName,
+			Message: err.Error(),
+			Rule:    name,
+			Code:    errorCode(name, currentFieldVal),
+			index:   index,
+			isElem:  index >= 0,
+		}, param); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// enterStruct returns the struct a field holds directly or through a non nil pointer.
+// Pointers already being walked are refused so self referencing graphs terminate.
+func (v *validation) enterStruct(currentFieldVal reflect.Value) (reflect.Value, bool) {
+	if currentFieldVal.Kind() == reflect.Pointer {
+		if currentFieldVal.IsNil() || v.visited[currentFieldVal.Pointer()] {
+			return reflect.Value{}, false
+		}
+		v.visited[currentFieldVal.Pointer()] = true
+	}
+	return structwalk.Indirect(currentFieldVal)
+}
+
+// leaveStruct releases a pointer marked by enterStruct once its struct has been walked
+func (v *validation) leaveStruct(currentFieldVal reflect.Value) {
+	if currentFieldVal.Kind() == reflect.Pointer {
+		delete(v.visited, currentFieldVal.Pointer())
+	}
+}
+
+// SetStrictMode makes validate tags on fields that are never validated a configuration error: unexported
+// fields and func, chan and unsafe pointer fields. They are skipped silently otherwise.
+func (v *Validator) SetStrictMode(strict bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.strict = strict
+}
+
+// unvalidatableField explains why a field is skipped, "" for fields that are validated.
+// Embedded structs, or pointers to structs, are walked even when unexported, their promoted fields
+// may be exported.
+func unvalidatableField(field structwalk.Field) string {
+	if !field.Walkable() {
+		return "unexported fields are not validated"
+	}
+	switch field.Type.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return fmt.Sprintf("%s fields are not validated", field.Type.Kind())
+	}
+	return ""
+}
+
+// hasRule reports whether rules contains the rule name
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if strings.Trim(rule, " ") == name {
+			return true
 		}
+	}
+	return false
+}
 
+// fieldPath joins a nested field name to the path of its parent struct ex: Address.City
+func fieldPath(prefix, fieldName string) string {
+	if prefix == "" {
+		return fieldName
 	}
+	return prefix + "." + fieldName
+}
 
+// presenceFirst moves required and omitempty ahead of the other rules, keeping the order of the rest,
+// so presence is settled before the value is checked whatever the position in the tag
+func presenceFirst(rules []string) []string {
+	ordered := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		if name := strings.Trim(rule, " "); name == required || name == omitEmpty {
+			ordered = append(ordered, rule)
+		}
+	}
+	for _, rule := range rules {
+		if name := strings.Trim(rule, " "); name != required && name != omitEmpty {
+			ordered = append(ordered, rule)
+		}
+	}
+	return ordered
 }
 
-func (v *Validator) applyValidationRule(rule string, currentFiledVal reflect.Value, fieldName string) error {
+// splitDive separates the rules for the field itself from the rules that apply to its elements
+func splitDive(rules []string) (fieldRules, elemRules []string, hasDive bool) {
+	for i, rule := range rules {
+		if strings.Trim(rule, " ") == dive {
+			return rules[:i], rules[i+1:], true
+		}
+	}
+	return rules, nil, false
+}
 
-	parts := strings.Split(rule, "=")
-	ruleName := strings.Trim(parts[0], " ")
-	var ruleValue string
+// elemName builds the reported name of a slice element ex: Items[2]
+func elemName(fieldName string, index int) string {
+	return fmt.Sprintf("%s[%d]", fieldName, index)
+}
 
-	// handle require
-	if len(parts) > 1 {
-		ruleValue = strings.Trim(parts[1], " ")
+// checkRuleKind returns an *InvalidRuleError when a built-in rule does not support the field kind
+func checkRuleKind(rule string, currentFieldVal reflect.Value, fieldName string) error {
+	ruleName, _ := parseRule(strings.TrimPrefix(strings.Trim(rule, " "), negation))
+	kinds, ok := ruleKinds[ruleName]
+	if !ok {
+		return nil
+	}
+	kind := currentFieldVal.Kind()
+	if bytesAsStringRules[ruleName] && isBytes(currentFieldVal) {
+		kind = reflect.String
+	}
+	// time rules accept time.Time but no other struct
+	if timeRules[ruleName] && kind == reflect.Struct && currentFieldVal.Type() != timeType {
+		return &InvalidRuleError{Field: fieldName, Rule: ruleName, Kind: kind}
+	}
+	for _, k := range kinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return &InvalidRuleError{Field: fieldName, Rule: ruleName, Kind: kind}
+}
+
+// parent is the struct holding the field, read by conditional rules.
+// Mistakes in the rule parameters are returned as an *InvalidRuleError.
+func (v *Validator) applyValidationRule(rule string, currentFiledVal reflect.Value, parent reflect.Value, fieldName string) error {
+
+	// !rule passes when rule fails
+	if inner, ok := strings.CutPrefix(strings.Trim(rule, " "), negation); ok {
+		err := v.applyValidationRule(inner, currentFiledVal, parent, fieldName)
+		if _, isRuleErr := err.(*InvalidRuleError); isRuleErr || err == errUnknownRule {
+			return err
+		}
+		if _, tooLong := err.(*regexInputError); tooLong {
+			return err
+		}
+		if err == nil {
+			return negatedError(inner)
+		}
+		return nil
+	}
+
+	ruleName, ruleValue := parseRule(rule)
+	if bytesAsStringRules[ruleName] && isBytes(currentFiledVal) {
+		currentFiledVal = reflect.ValueOf(string(currentFiledVal.Bytes()))
 	}
 
 	switch ruleName {
 	case required:
 		if currentFiledVal.IsZero() {
-			return fmt.Errorf("field is required")
+			return defaultMessage(CodeRequired, "")
+		}
+	case notBlank:
+		if strings.TrimSpace(currentFiledVal.String()) == "" {
+			return defaultMessage(CodeNotBlank, "")
+		}
+	case isDefault:
+		if !currentFiledVal.IsZero() {
+			return defaultMessage(CodeMustBeDefault, "")
 		}
 	case min:
 		return v.validateMin(currentFiledVal, ruleValue)
 	case max:
 		return v.validateMax(currentFiledVal, ruleValue)
+	case rangeRule:
+		return v.validateRange(currentFiledVal, ruleValue)
+	case digits:
+		return v.validateDigits(currentFiledVal, ruleValue)
+	case decimal:
+		return v.validateDecimal(currentFiledVal, ruleValue)
 	case email:
 		if !v.isMatchedRegex(currentFiledVal.String(), emailRegexPattern) {
-			return fmt.Errorf("invalid email format")
+			return defaultMessage(CodeEmail, "")
 		}
 	case regex:
-		if !v.isMatchedRegex(currentFiledVal.String(), ruleValue) {
-			return fmt.Errorf("value does not match required format")
+		return v.matchRegexRule(currentFiledVal.String(), ruleValue, fieldName)
+	case oneOf:
+		if !isOneOf(currentFiledVal, ruleValue) {
+			return defaultMessage(CodeOneOf, listParam(ruleValue))
 		}
+	case contains:
+		if !strings.Contains(currentFiledVal.String(), ruleValue) {
+			return defaultMessage(CodeContains, strconv.Quote(ruleValue))
+		}
+	case e164:
+		if !v.isMatchedRegex(currentFiledVal.String(), e164RegexPattern) {
+			return defaultMessage(CodeE164, "")
+		}
+	case jsonRule:
+		if !json.Valid([]byte(currentFiledVal.String())) {
+			return defaultMessage(CodeJSON, "")
+		}
+	case gteNow, lteNow, within:
+		return v.validateRelativeTime(ruleName, currentFiledVal, ruleValue)
+	case requiredIf, requiredIfAny:
+		return validateRequiredIf(ruleName, currentFiledVal, parent, fieldName, ruleValue)
+	case uniqueWith:
+		return validateUniqueWith(currentFiledVal, parent, fieldName, ruleValue)
+	case dive, structOnly, skipUnless, omitEmpty:
+		// markers handled while walking the struct
+	default:
+		return errUnknownRule
 	}
 
 	return nil
 }
 
-func (v *Validator) validateMin(currentFieldVal reflect.Value, minVlaue string) error {
+// splitAlternatives splits rule a|b into its alternatives when every part names a built-in or
+// custom rule, so patterns such as regex=^(a|b)$ are left whole. It returns false, and no
+// alternatives, for a single rule.
+func (v *Validator) splitAlternatives(rule string) ([]string, bool) {
+	if !strings.Contains(rule, orSeparator) {
+		return nil, false
+	}
+	alternatives := splitUnquoted(rule, orSeparator[0])
+	if len(alternatives) == 1 {
+		return nil, false
+	}
 
-	min, err := strconv.Atoi(minVlaue)
+	for _, alternative := range alternatives {
+		name, _ := parseRule(strings.TrimPrefix(strings.Trim(alternative, " "), negation))
+		if !builtinRules[name] && !v.hasCustomValidator(name) {
+			return nil, false
+		}
+	}
+	return alternatives, true
+}
+
+// applyAlternatives passes when any of the alternatives passes, built-in or custom,
+// otherwise it fails with the messages of all of them
+func (v *validation) applyAlternatives(alternatives []string, currentFieldVal reflect.Value, parent reflect.Value, fieldName string) error {
+	var errMsgs []string
+	var timeoutErr *TimeoutError
+	for _, alternative := range alternatives {
+		err := v.applyValidationRule(alternative, currentFieldVal, parent, fieldName)
+		if err == errUnknownRule {
+			err = v.applyCustomRule(alternative, currentFieldVal, parent)
+		}
+		if _, isRuleErr := err.(*InvalidRuleError); isRuleErr || err == nil {
+			return err
+		}
+		// another alternative may still pass, the field is only undecided when none does
+		if timedOut, isTimeout := err.(*TimeoutError); isTimeout {
+			timeoutErr = timedOut
+			continue
+		}
+		errMsgs = append(errMsgs, err.Error())
+	}
+	if timeoutErr != nil {
+		return timeoutErr
+	}
+	return fmt.Errorf("%s", strings.Join(errMsgs, " or "))
+}
+
+// negatedError is the failure of !rule, reported when rule itself passes
+func negatedError(rule string) error {
+	ruleName, ruleValue := parseRule(rule)
+	code := negatedCodePrefix + strings.TrimPrefix(ruleCodes[ruleName], codePrefix)
+	if _, ok := defaultMessages[code]; !ok {
+		return defaultMessage(negatedRuleMessage, rule)
+	}
+	switch ruleName {
+	case oneOf:
+		ruleValue = listParam(ruleValue)
+	case contains:
+		ruleValue = strconv.Quote(ruleValue)
+	}
+	return defaultMessage(code, ruleValue)
+}
+
+// applyCustomRule runs the custom validator or the Rule named by rule, honoring ! negation
+func (v *validation) applyCustomRule(rule string, currentFieldVal reflect.Value, parent reflect.Value) error {
+	name, negated := strings.CutPrefix(strings.Trim(rule, " "), negation)
+	ruleName, _ := parseRule(name)
+	ctx := v.ctx
+	timeout, timed := v.ruleTimeout(ruleName)
+	if timed {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	validator, ok := v.customValidator(name, currentFieldVal, parent)
+	if !ok {
+		validator, ok = v.ruleValidator(ctx, name)
+	}
+	if !ok {
+		return errUnknownRule
+	}
+
+	var err error
+	if timed {
+		err = callWithin(ctx, ruleName, timeout, func() error {
+			return validator(currentFieldVal, parent)
+		})
+	} else {
+		err = validator(currentFieldVal, parent)
+	}
+	if _, isTimeout := err.(*TimeoutError); isTimeout {
+		return err
+	}
+	if _, isRuleErr := err.(*InvalidRuleError); isRuleErr {
+		return err
+	}
+	if !negated {
+		// only the messages of custom validators may quote the value, built-in ones never do
+		if err != nil && v.mask != "" {
+			err = errors.New(redactMessage(err.Error(), currentFieldVal, v.mask))
+		}
+		return err
+	}
 	if err != nil {
-		return fmt.Errorf("invalid min value")
+		return nil
 	}
+	return negatedError(name)
+}
 
+// isOneOf reports whether the field, a string or an integer, equals one of the space separated options
+func isOneOf(currentFieldVal reflect.Value, options string) bool {
+	var value string
 	switch currentFieldVal.Kind() {
 	case reflect.String:
-		if len(currentFieldVal.String()) < min {
-			return fmt.Errorf("length must be at least %d", min)
-		}
+		value = currentFieldVal.String()
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if currentFieldVal.Int() < int64(min) {
-			return fmt.Errorf("value must be at least %d", min)
+		value = strconv.FormatInt(currentFieldVal.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = strconv.FormatUint(currentFieldVal.Uint(), 10)
+	}
+
+	for _, option := range strings.Fields(options) {
+		if option == value {
+			return true
 		}
 	}
+	return false
+}
+
+// parseRule splits a rule like min=2 into its name and parameter, the parameter is unquoted
+// and may itself contain = ex: regex='^a=b$'
+func parseRule(rule string) (name, param string) {
+	name, param, _ = strings.Cut(rule, "=")
+	return strings.Trim(name, " "), unquoteParam(strings.Trim(param, " "))
+}
+
+// validateMin checks min=N against the length of strings, the value of numbers and the size of collections
+func (v *Validator) validateMin(currentFieldVal reflect.Value, minVlaue string) error {
+
+	bound, err := strconv.Atoi(minVlaue)
+	if err != nil {
+		return &InvalidRuleError{Rule: min, Reason: fmt.Sprintf("expects an integer ex: min=2, got %q", minVlaue)}
+	}
+
+	var failed bool
+	switch currentFieldVal.Kind() {
+	case reflect.String:
+		failed = len(currentFieldVal.String()) < bound
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		failed = currentFieldVal.Int() < int64(bound)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		failed = bound > 0 && currentFieldVal.Uint() < uint64(bound)
+	case reflect.Float32, reflect.Float64:
+		failed = currentFieldVal.Float() < float64(bound)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		failed = currentFieldVal.Len() < bound
+	}
 
+	if failed {
+		return defaultMessage(errorCode(min, currentFieldVal), strconv.Itoa(bound))
+	}
 	return nil
 
 }
 
+// validateMax checks max=N, measuring the field like validateMin
 func (v *Validator) validateMax(currentFieldVal reflect.Value, maxValue string) error {
-	max, err := strconv.Atoi(maxValue)
+	bound, err := strconv.Atoi(maxValue)
 	if err != nil {
-		return fmt.Errorf("invalid max value")
+		return &InvalidRuleError{Rule: max, Reason: fmt.Sprintf("expects an integer ex: max=10, got %q", maxValue)}
 	}
 
+	var failed bool
 	switch currentFieldVal.Kind() {
 	case reflect.String:
-		if len(currentFieldVal.String()) > max {
-			return fmt.Errorf("length must be at most %d", max)
-		}
+		failed = len(currentFieldVal.String()) > bound
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		if currentFieldVal.Int() > int64(max) {
-			return fmt.Errorf("length must be at most %d", max)
+		failed = currentFieldVal.Int() > int64(bound)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		failed = bound < 0 || currentFieldVal.Uint() > uint64(bound)
+	case reflect.Float32, reflect.Float64:
+		failed = currentFieldVal.Float() > float64(bound)
+	case reflect.Slice, reflect.Array, reflect.Map:
+		failed = currentFieldVal.Len() > bound
+	}
+
+	if failed {
+		return defaultMessage(errorCode(max, currentFieldVal), strconv.Itoa(bound))
+	}
+	return nil
+
+}
+
+// validateRange checks range=low:high, measuring the field like min and max do
+func (v *Validator) validateRange(currentFieldVal reflect.Value, rangeValue string) error {
+	low, high, ok := rangeBounds(rangeValue)
+	if !ok {
+		return &InvalidRuleError{Rule: rangeRule, Reason: fmt.Sprintf("expects low:high with low at most high ex: range=1:10, got %q", rangeValue)}
+	}
+
+	var measured float64
+	switch currentFieldVal.Kind() {
+	case reflect.String:
+		measured = float64(len(currentFieldVal.String()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		measured = float64(currentFieldVal.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		measured = float64(currentFieldVal.Uint())
+	case reflect.Float32, reflect.Float64:
+		measured = currentFieldVal.Float()
+	case reflect.Slice, reflect.Array, reflect.Map:
+		measured = float64(currentFieldVal.Len())
+	default:
+		return nil
+	}
+
+	if measured < low || measured > high {
+		return defaultMessage(errorCode(rangeRule, currentFieldVal), "", "{low}", boundParam(low), "{high}", boundParam(high))
+	}
+	return nil
+}
+
+// rangeBounds parses the low:high parameter of range, ok is false unless low is at most high
+func rangeBounds(rangeValue string) (low, high float64, ok bool) {
+	lowValue, highValue, found := strings.Cut(rangeValue, ":")
+	low, lowErr := strconv.ParseFloat(strings.Trim(lowValue, " "), 64)
+	high, highErr := strconv.ParseFloat(strings.Trim(highValue, " "), 64)
+	return low, high, found && lowErr == nil && highErr == nil && low <= high
+}
+
+// validateDigits checks digits=N, the exact number of digits of an integer or a numeric string
+func (v *Validator) validateDigits(currentFieldVal reflect.Value, digitsValue string) error {
+	count, err := strconv.Atoi(digitsValue)
+	if err != nil || count < 1 {
+		return &InvalidRuleError{Rule: digits, Reason: fmt.Sprintf("expects a positive integer ex: digits=6, got %q", digitsValue)}
+	}
+
+	intPart, fracPart, ok := numberParts(currentFieldVal)
+	if !ok || fracPart != "" {
+		return defaultMessage(digitsOnlyMessage, "")
+	}
+	if len(intPart) != count {
+		return defaultMessage(CodeDigits, strconv.Itoa(count))
+	}
+	return nil
+}
+
+// validateDecimal checks decimal=P:S, at most P integer digits and S fraction digits
+func (v *Validator) validateDecimal(currentFieldVal reflect.Value, decimalValue string) error {
+	precision, scale, ok := decimalBounds(decimalValue)
+	if !ok {
+		return &InvalidRuleError{Rule: decimal, Reason: fmt.Sprintf("expects precision:scale ex: decimal=8:2, got %q", decimalValue)}
+	}
+
+	intPart, fracPart, ok := numberParts(currentFieldVal)
+	if !ok {
+		return defaultMessage(numberMessage, "")
+	}
+	if len(strings.TrimLeft(intPart, "0")) > precision || len(fracPart) > scale {
+		return defaultMessage(CodeDecimal, "", "{precision}", strconv.Itoa(precision), "{scale}", strconv.Itoa(scale))
+	}
+	return nil
+}
+
+// decimalBounds parses the precision:scale parameter of decimal, a positive precision and a scale
+// of at least 0
+func decimalBounds(decimalValue string) (precision, scale int, ok bool) {
+	precisionValue, scaleValue, found := strings.Cut(decimalValue, ":")
+	precision, precisionErr := strconv.Atoi(strings.Trim(precisionValue, " "))
+	scale, scaleErr := strconv.Atoi(strings.Trim(scaleValue, " "))
+	return precision, scale, found && precisionErr == nil && scaleErr == nil && precision > 0 && scale >= 0
+}
+
+// numberParts returns the integer and fraction digits of a number, sign excluded.
+// Strings must hold a plain decimal number ex: -12.50
+func numberParts(currentFieldVal reflect.Value) (intPart, fracPart string, ok bool) {
+	var text string
+	switch currentFieldVal.Kind() {
+	case reflect.String:
+		text = currentFieldVal.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		text = strconv.FormatInt(currentFieldVal.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		text = strconv.FormatUint(currentFieldVal.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		text = strconv.FormatFloat(currentFieldVal.Float(), 'f', -1, currentFieldVal.Type().Bits())
+	default:
+		return "", "", false
+	}
+
+	text = strings.TrimPrefix(text, "-")
+	intPart, fracPart, _ = strings.Cut(text, ".")
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return "", "", false
+	}
+	return intPart, fracPart, true
+}
+
+// isBytes reports whether the field is a byte slice ex: []byte or json.RawMessage
+func isBytes(currentFieldVal reflect.Value) bool {
+	return currentFieldVal.Kind() == reflect.Slice && currentFieldVal.Type().Elem().Kind() == reflect.Uint8
+}
+
+// isDigits reports whether s only holds ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateRelativeTime checks gte_now, lte_now and within=duration against the current time
+func (v *Validator) validateRelativeTime(ruleName string, currentFieldVal reflect.Value, ruleValue string) error {
+	var limit time.Duration
+	if ruleName == within {
+		parsed, err := time.ParseDuration(ruleValue)
+		if err != nil {
+			return &InvalidRuleError{Rule: within, Reason: fmt.Sprintf("expects a duration ex: within=24h, got %q", ruleValue)}
+		}
+		limit = parsed
+	}
+
+	var t time.Time
+	if currentFieldVal.Kind() == reflect.String {
+		parsed, err := time.Parse(time.RFC3339, currentFieldVal.String())
+		if err != nil {
+			return defaultMessage(timeMessage, "")
 		}
+		t = parsed
+	} else if currentFieldVal.CanInterface() {
+		t = currentFieldVal.Interface().(time.Time)
+	} else {
+		// an unexported time.Time cannot be read
+		return nil
+	}
+
+	now := time.Now()
+	switch ruleName {
+	case gteNow:
+		if t.Before(now) {
+			return defaultMessage(CodeNotBeforeNow, "")
+		}
+	case lteNow:
+		if t.After(now) {
+			return defaultMessage(CodeNotAfterNow, "")
+		}
+	case within:
+		if diff := t.Sub(now); diff > limit || diff < -limit {
+			return defaultMessage(CodeWithin, limit.String())
+		}
+	}
+	return nil
+}
 
+// validateRequiredIf checks required_if=Field value [Field value ...], requiring the field when every
+// condition holds, and required_if_any which requires it when any condition holds
+func validateRequiredIf(ruleName string, currentFieldVal reflect.Value, parent reflect.Value, fieldName string, ruleValue string) error {
+	matched, conditions, err := fieldConditions(ruleName, parent, fieldName, ruleValue, ruleName == requiredIf)
+	if err != nil {
+		return err
+	}
+
+	if matched && currentFieldVal.IsZero() {
+		return defaultMessage(ruleCodes[ruleName], conditionsParam(ruleName, conditions))
 	}
 	return nil
+}
 
+// conditionsParam joins the conditions of required_if with and, those of required_if_any with or
+func conditionsParam(ruleName string, conditions []string) string {
+	if ruleName == requiredIfAny {
+		return strings.Join(conditions, " or ")
+	}
+	return strings.Join(conditions, " and ")
 }
 
-func (v *Validator) isMatchedRegex(value, pattern string) bool {
+// validateUniqueWith checks unique_with=Field [Field ...], a set field must differ from each of the named fields
+// ex: NewPassword must not equal OldPassword
+func validateUniqueWith(currentFieldVal reflect.Value, parent reflect.Value, fieldName string, ruleValue string) error {
+	others := strings.Fields(ruleValue)
+	if len(others) == 0 {
+		return &InvalidRuleError{Field: fieldName, Rule: uniqueWith, Reason: "expects field names"}
+	}
+	var clashes []string
+	for _, other := range others {
+		otherVal, ok := fieldByName(parent, other)
+		if !ok {
+			return &InvalidRuleError{Field: fieldName, Rule: uniqueWith, Reason: fmt.Sprintf("unknown field %s", other)}
+		}
+		// presence is left to required, an empty value clashes with nothing
+		if !currentFieldVal.IsZero() && sameValue(currentFieldVal, otherVal) {
+			clashes = append(clashes, other)
+		}
+	}
+	if len(clashes) > 0 {
+		return defaultMessage(CodeUniqueWith, strings.Join(clashes, " and "))
+	}
+	return nil
+}
 
-	matched, _ := regexp.MatchString(pattern, value)
-	return matched
+// sameValue reports whether two field values are equal, non nil pointers are compared by the values they point to
+func sameValue(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Pointer && b.Kind() == reflect.Pointer && a.IsNil() != b.IsNil() {
+		return false
+	}
+	for _, val := range []*reflect.Value{&a, &b} {
+		if val.Kind() == reflect.Pointer && !val.IsNil() {
+			*val = val.Elem()
+		}
+	}
+	if a.CanInterface() && b.CanInterface() {
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+	return a.Type() == b.Type() && valueString(a) == valueString(b)
 }
 
-func (v *Validator) applyCustomValidators(structVal reflect.Value) {
+// fieldConditions evaluates the Field value pairs of a conditional rule against parent, all of them when
+// all is set or any of them otherwise. conditions describes each pair for messages ex: Type is business
+func fieldConditions(ruleName string, parent reflect.Value, fieldName string, ruleValue string, all bool) (matched bool, conditions []string, err error) {
+	params := strings.Fields(ruleValue)
+	if len(params) == 0 || len(params)%2 != 0 {
+		return false, nil, &InvalidRuleError{Field: fieldName, Rule: ruleName, Reason: "expects field value pairs"}
+	}
 
-	// get type
-	structType := structVal.Type()
+	matched = all
+	for i := 0; i < len(params); i += 2 {
+		other, expected := params[i], params[i+1]
+		otherVal, ok := fieldByName(parent, other)
+		if !ok {
+			return false, nil, &InvalidRuleError{Field: fieldName, Rule: ruleName, Reason: fmt.Sprintf("unknown field %s", other)}
+		}
 
-	for i := 0; i < structVal.NumField(); i++ {
-		currentField := structType.Field(i)
-		currentFieldVal := structVal.Field(i)
+		holds := valueString(otherVal) == expected
+		if all {
+			matched = matched && holds
+		} else {
+			matched = matched || holds
+		}
+		conditions = append(conditions, fmt.Sprintf("%s is %s", other, expected))
+	}
+	return matched, conditions, nil
+}
 
-		// Get validation rules from struct tag `validate:"required,min=2,max=50"`
-		tagVal := currentField.Tag.Get(validate)
-		if tagVal == "" {
+// applySkipUnless drops the skip_unless=Field value markers from rules. When a marker's conditions do
+// not all hold, the rules following it are dropped too and skipped reports that the field, its elements
+// and nested structs must not be walked any further.
+func applySkipUnless(rules []string, parent reflect.Value, fieldName string) (kept []string, skipped bool, err error) {
+	for _, rule := range rules {
+		name, param := parseRule(rule)
+		if name != skipUnless {
+			kept = append(kept, rule)
 			continue
 		}
+		matched, _, err := fieldConditions(skipUnless, parent, fieldName, param, true)
+		if err != nil {
+			return nil, false, err
+		}
+		if !matched {
+			return kept, true, nil
+		}
+	}
+	return kept, false, nil
+}
 
-		rules := strings.Split(tagVal, ",")
-
-		for _, rule := range rules {
-			// Check if this rule is a custom validator
-			if validator, ok := v.customValidators[rule]; ok {
-				// execute validator
-				if err := validator(currentFieldVal); err != nil {
-					v.errors = append(v.errors, ValidationError{
-						Field:   currentField.Name,
-						Message: err.Error(),
-					})
-				}
-			}
+// fieldByName returns the named field of parent, looking through a pointer parent
+func fieldByName(parent reflect.Value, name string) (reflect.Value, bool) {
+	if parent.Kind() == reflect.Pointer {
+		parent = parent.Elem()
+	}
+	if parent.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	field := parent.FieldByName(name)
+	return field, field.IsValid()
+}
 
+// valueString renders a field value for comparison with a rule parameter, non nil pointers are dereferenced
+func valueString(val reflect.Value) string {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return ""
 		}
+		val = val.Elem()
+	}
+	switch val.Kind() {
+	case reflect.String:
+		return val.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool())
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, val.Type().Bits())
+	}
+	if val.CanInterface() {
+		return fmt.Sprint(val.Interface())
+	}
+	return ""
+}
 
+func (v *Validator) isMatchedRegex(value, pattern string) bool {
+	re, err := compiledRegex(pattern)
+	if err != nil {
+		return false
 	}
+	return re.MatchString(value)
+}
 
+// regexCache holds the compiled regexp of the patterns matched, tags being parsed once per type
+// the same patterns come back on every call. It keeps up to maxCachedRegexes patterns, so rule
+// strings built at run time cannot grow it without bound.
+var (
+	regexCache   sync.Map
+	cachedRegexs atomic.Int64
+)
+
+// maxCachedRegexes bounds the patterns held by regexCache
+const maxCachedRegexes = 1024
+
+// compiledRegex returns pattern compiled, from regexCache after the first use
+func compiledRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if cachedRegexs.Add(1) <= maxCachedRegexes {
+		regexCache.Store(pattern, re)
+	}
+	return re, nil
 }
 
 type User struct {
@@ -321,7 +1638,7 @@ func ExampleFour() {
 	v.RegisterCustomValidator("valid_username", func(field reflect.Value) error {
 		usernameValue := field.String()
 		if !strings.Contains(usernameValue, "_") {
-			fmt.Errorf("username must contain underscore")
+			return fmt.Errorf("username must contain underscore")
 		}
 		return nil
 	})